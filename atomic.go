@@ -0,0 +1,181 @@
+package queue
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicLengthQueue is a wrapper that makes a queue safe for concurrent use. Push, Pop and Peek hold a mutex while
+// they use the wrapped queue, but the length is kept in an atomic counter so that Length never waits for the mutex.
+type AtomicLengthQueue[Element any] struct {
+	mu     sync.Mutex
+	q      Queue[Element]
+	length atomic.Int64
+
+	// id orders the locking of two wrappers by TransferOne.
+	id uint64
+}
+
+var _ Queue[int] = (*AtomicLengthQueue[int])(nil)
+
+var nextAtomicLengthQueueID atomic.Uint64
+
+// NewAtomicLengthQueue returns a new concurrency-safe wrapper around q.
+// The wrapped queue must not be used directly once it has been wrapped.
+func NewAtomicLengthQueue[Element any](q Queue[Element]) *AtomicLengthQueue[Element] {
+	w := &AtomicLengthQueue[Element]{q: q, id: nextAtomicLengthQueueID.Add(1)}
+	w.length.Store(int64(q.Length()))
+
+	return w
+}
+
+// Push adds an element to the end of the queue. If the queue cannot accept more elements, the ErrQueueFull error is returned.
+func (w *AtomicLengthQueue[Element]) Push(item Element) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.q.Push(item); err != nil {
+		return err
+	}
+
+	w.length.Store(int64(w.q.Length()))
+
+	return nil
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (w *AtomicLengthQueue[Element]) Pop() (Element, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	item, err := w.q.Pop()
+	if err != nil {
+		return item, err
+	}
+
+	w.length.Store(int64(w.q.Length()))
+
+	return item, nil
+}
+
+// PopWithLen removes and returns the first element of the queue, along with the number of elements that remain once it
+// is removed, which later pushes and pops cannot affect. If the queue is empty, the ErrQueueEmpty error is returned.
+func (w *AtomicLengthQueue[Element]) PopWithLen() (Element, int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	item, err := w.q.Pop()
+	if err != nil {
+		return item, 0, err
+	}
+
+	w.length.Store(int64(w.q.Length()))
+
+	return item, w.q.Length(), nil
+}
+
+// PopAndDo removes the first element of the queue and calls fn with it while still holding the mutex, so that other
+// goroutines cannot use the queue until fn returns. The element is removed whether or not fn succeeds, and the error
+// returned by fn is returned. If the queue is empty, fn is not called and the ErrQueueEmpty error is returned.
+// fn must not call methods on the same queue, such as Push, Pop, Peek or PopAndDo, as they would wait for the mutex
+// forever.
+func (w *AtomicLengthQueue[Element]) PopAndDo(fn func(Element) error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	item, err := w.q.Pop()
+	if err != nil {
+		return err
+	}
+
+	w.length.Store(int64(w.q.Length()))
+
+	return fn(item)
+}
+
+// CompareAndPopFront removes the first element of the queue if it is equal to expected, as determined by the equal
+// function, holding the mutex so that no other goroutine can change the first element in between. It reports whether
+// the element was removed. If the queue is empty, the ErrQueueEmpty error is returned.
+func (w *AtomicLengthQueue[Element]) CompareAndPopFront(expected Element, equal func(a, b Element) bool) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	item, err := w.q.Peek()
+	if err != nil {
+		return false, err
+	}
+
+	if !equal(item, expected) {
+		return false, nil
+	}
+
+	_, _ = w.q.Pop()
+	w.length.Store(int64(w.q.Length()))
+
+	return true, nil
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (w *AtomicLengthQueue[Element]) Peek() (Element, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.q.Peek()
+}
+
+// Length returns the number of elements in the queue, without waiting for concurrent pushes or pops to finish.
+func (w *AtomicLengthQueue[Element]) Length() int {
+	return int(w.length.Load())
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them.
+// The iterator yields a snapshot of the elements taken while holding the mutex when iteration begins.
+func (w *AtomicLengthQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		w.mu.Lock()
+		items := slices.Collect(w.q.All())
+		w.mu.Unlock()
+
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// TransferOne moves the first element of src to the end of dst as a single step, holding the mutexes of both queues
+// so that no other goroutine can observe the element in neither or both of them. If src is empty, the ErrQueueEmpty
+// error is returned. If dst cannot accept more elements, the ErrQueueFull error is returned and src is unchanged.
+func TransferOne[Element any](src, dst *AtomicLengthQueue[Element]) error {
+	// locking in a fixed order prevents deadlock between concurrent transfers in opposite directions.
+	first, second := src, dst
+	if second.id < first.id {
+		first, second = second, first
+	}
+
+	first.mu.Lock()
+	defer first.mu.Unlock()
+
+	if second != first {
+		second.mu.Lock()
+		defer second.mu.Unlock()
+	}
+
+	item, err := src.q.Peek()
+	if err != nil {
+		return err
+	}
+
+	if err := dst.q.Push(item); err != nil {
+		return err
+	}
+
+	_, _ = src.q.Pop()
+	src.length.Store(int64(src.q.Length()))
+	dst.length.Store(int64(dst.q.Length()))
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package queue
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicLengthQueue(t *testing.T) {
+	t.Run("wraps queue operations", func(t *testing.T) {
+		inner := NewBoundedQueue[int](2)
+		assert.NoError(t, inner.Push(1))
+
+		q := NewAtomicLengthQueue(inner)
+		assert.Equal(t, 1, q.Length())
+		assert.NoError(t, q.Push(2))
+		assert.ErrorIs(t, q.Push(3), ErrQueueFull)
+		assert.Equal(t, 2, q.Length())
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+
+		for range 2 {
+			_, err := q.Pop()
+			assert.NoError(t, err)
+		}
+
+		_, err = q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+		assert.Equal(t, 0, q.Length())
+	})
+
+	t.Run("length follows drop policy queue", func(t *testing.T) {
+		for _, policy := range []OverflowPolicy{DropOldest, DropNewest} {
+			q := NewAtomicLengthQueue[int](NewBoundedQueuePolicy[int](2, policy))
+			for i := range 5 {
+				assert.NoError(t, q.Push(i))
+			}
+
+			assert.Equal(t, 2, q.Length())
+
+			_, length, err := q.PopWithLen()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, length)
+			assert.Equal(t, 1, q.Length())
+		}
+	})
+
+	t.Run("all yields snapshot of elements", func(t *testing.T) {
+		q := NewAtomicLengthQueue(NewUnboundedQueue[int](2))
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		for x := range q.All() {
+			assert.NoError(t, q.Push(x+10))
+		}
+
+		assert.Equal(t, []int{1, 2, 11, 12}, slices.Collect(q.All()))
+	})
+
+	t.Run("pop with len keeps length in step", func(t *testing.T) {
+		q := NewAtomicLengthQueue(NewUnboundedQueue[int](2))
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		x, length, err := q.PopWithLen()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+		assert.Equal(t, 1, length)
+		assert.Equal(t, 1, q.Length())
+
+		_, _, err = q.PopWithLen()
+		assert.NoError(t, err)
+		_, _, err = q.PopWithLen()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+		assert.Equal(t, 0, q.Length())
+	})
+
+	t.Run("pop and do removes element even when fn fails", func(t *testing.T) {
+		q := NewAtomicLengthQueue(NewUnboundedQueue[int](2))
+		assert.NoError(t, q.Push(1))
+		errProcess := errors.New("cannot process")
+
+		var got int
+		err := q.PopAndDo(func(x int) error {
+			got = x
+			return errProcess
+		})
+
+		assert.ErrorIs(t, err, errProcess)
+		assert.Equal(t, 1, got)
+		assert.Equal(t, 0, q.Length())
+
+		called := false
+		err = q.PopAndDo(func(int) error {
+			called = true
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+		assert.False(t, called)
+	})
+
+	t.Run("concurrent pop and do processes each element once", func(t *testing.T) {
+		const workerCount = 4
+		const itemCount = 1000
+
+		q := NewAtomicLengthQueue(NewUnboundedQueue[int](itemCount))
+		for i := range itemCount {
+			assert.NoError(t, q.Push(i))
+		}
+
+		// the map is only used inside fn, which PopAndDo runs under the queue's mutex.
+		processed := make(map[int]int)
+
+		var wg sync.WaitGroup
+		for range workerCount {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				for {
+					err := q.PopAndDo(func(x int) error {
+						processed[x]++
+						return nil
+					})
+					if err != nil {
+						return
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+		assert.Len(t, processed, itemCount)
+		for x, count := range processed {
+			assert.Equal(t, 1, count, "element %d", x)
+		}
+	})
+
+	t.Run("compare and pop front only pops expected element", func(t *testing.T) {
+		q := NewAtomicLengthQueue(NewUnboundedQueue[int](2))
+		equal := func(a, b int) bool { return a == b }
+
+		_, err := q.CompareAndPopFront(1, equal)
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		popped, err := q.CompareAndPopFront(2, equal)
+		assert.NoError(t, err)
+		assert.False(t, popped)
+		assert.Equal(t, 2, q.Length())
+
+		popped, err = q.CompareAndPopFront(1, equal)
+		assert.NoError(t, err)
+		assert.True(t, popped)
+		assert.Equal(t, 1, q.Length())
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+	})
+
+	t.Run("length reads during concurrent pushes and pops", func(t *testing.T) {
+		const workerCount = 4
+		const itemsPerWorker = 1000
+
+		q := NewAtomicLengthQueue(NewUnboundedQueue[int](0))
+
+		var wg sync.WaitGroup
+		for range workerCount {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+				for i := range itemsPerWorker {
+					assert.NoError(t, q.Push(i))
+				}
+			}()
+
+			go func() {
+				defer wg.Done()
+				for range itemsPerWorker / 2 {
+					for {
+						if _, err := q.Pop(); err == nil {
+							break
+						}
+					}
+				}
+			}()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		for reading := true; reading; {
+			select {
+			case <-done:
+				reading = false
+			default:
+				length := q.Length()
+				assert.GreaterOrEqual(t, length, 0)
+				assert.LessOrEqual(t, length, workerCount*itemsPerWorker)
+			}
+		}
+
+		assert.Equal(t, workerCount*itemsPerWorker/2, q.Length())
+	})
+}
+
+func TestTransferOne(t *testing.T) {
+	t.Run("moves front element", func(t *testing.T) {
+		src := NewAtomicLengthQueue(NewUnboundedQueue[int](2))
+		dst := NewAtomicLengthQueue(NewBoundedQueue[int](1))
+		assert.NoError(t, src.Push(1))
+		assert.NoError(t, src.Push(2))
+
+		assert.NoError(t, TransferOne(src, dst))
+		assert.Equal(t, 1, src.Length())
+		assert.Equal(t, 1, dst.Length())
+
+		x, err := dst.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+	})
+
+	t.Run("leaves source unchanged when destination is full", func(t *testing.T) {
+		src := NewAtomicLengthQueue(NewUnboundedQueue[int](1))
+		dst := NewAtomicLengthQueue(NewBoundedQueue[int](1))
+		assert.NoError(t, src.Push(1))
+		assert.NoError(t, dst.Push(2))
+
+		assert.ErrorIs(t, TransferOne(src, dst), ErrQueueFull)
+		assert.Equal(t, 1, src.Length())
+
+		x, err := src.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+	})
+
+	t.Run("cannot transfer from empty queue", func(t *testing.T) {
+		src := NewAtomicLengthQueue(NewUnboundedQueue[int](1))
+		dst := NewAtomicLengthQueue(NewUnboundedQueue[int](1))
+
+		assert.ErrorIs(t, TransferOne(src, dst), ErrQueueEmpty)
+		assert.Equal(t, 0, dst.Length())
+	})
+
+	t.Run("concurrent transfers neither lose nor duplicate elements", func(t *testing.T) {
+		const itemCount = 100
+		const transfersPerWorker = 1000
+
+		a := NewAtomicLengthQueue(NewUnboundedQueue[int](itemCount))
+		b := NewAtomicLengthQueue(NewUnboundedQueue[int](itemCount))
+		for i := range itemCount {
+			assert.NoError(t, a.Push(i))
+		}
+
+		var wg sync.WaitGroup
+		for _, pair := range [][2]*AtomicLengthQueue[int]{{a, b}, {b, a}, {a, b}, {b, a}} {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				for range transfersPerWorker {
+					_ = TransferOne(pair[0], pair[1])
+				}
+			}()
+		}
+
+		wg.Wait()
+		assert.Equal(t, itemCount, a.Length()+b.Length())
+
+		seen := make(map[int]bool)
+		for _, q := range []*AtomicLengthQueue[int]{a, b} {
+			for {
+				x, err := q.Pop()
+				if err != nil {
+					break
+				}
+
+				assert.False(t, seen[x], "element %d is duplicated", x)
+				seen[x] = true
+			}
+		}
+
+		assert.Len(t, seen, itemCount)
+	})
+}
+
+func BenchmarkAtomicLengthQueueLength(b *testing.B) {
+	// a background writer keeps the mutex contended while lengths are read.
+	runWithWriter := func(b *testing.B, push func(), readLength func() int) {
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					push()
+				}
+			}
+		}()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = readLength()
+			}
+		})
+
+		close(stop)
+		wg.Wait()
+	}
+
+	b.Run("mutex", func(b *testing.B) {
+		var mu sync.Mutex
+		q := NewUnboundedQueue[int](0)
+
+		runWithWriter(b, func() {
+			mu.Lock()
+			_ = q.Push(1)
+			_, _ = q.Pop()
+			mu.Unlock()
+		}, func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return q.Length()
+		})
+	})
+
+	b.Run("atomic", func(b *testing.B) {
+		q := NewAtomicLengthQueue(NewUnboundedQueue[int](0))
+
+		runWithWriter(b, func() {
+			_ = q.Push(1)
+			_, _ = q.Pop()
+		}, q.Length)
+	})
+}
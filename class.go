@@ -0,0 +1,105 @@
+package queue
+
+import "iter"
+
+// ClassQueue is a queue that divides its elements into a fixed number of priority classes. Pop takes elements from
+// the highest priority class that has any, and elements within a class are popped in the order they were pushed.
+// Class zero has the highest priority.
+type ClassQueue[Element any] struct {
+	queues []Queue[Element]
+	class  func(Element) int
+}
+
+var _ Queue[int] = (*ClassQueue[int])(nil)
+
+// NewClassQueue returns a new queue with the given number of priority classes, which assigns each pushed element
+// to the class returned by the class function.
+func NewClassQueue[Element any](classes int, class func(Element) int) *ClassQueue[Element] {
+	queues := make([]Queue[Element], classes)
+	for i := range queues {
+		queues[i] = NewUnboundedQueue[Element](0)
+	}
+
+	return &ClassQueue[Element]{
+		queues: queues,
+		class:  class,
+	}
+}
+
+// Push adds an element to the end of its class. If its class is outside of [0, classes), the ErrInvalidClass error is returned.
+func (q *ClassQueue[Element]) Push(item Element) error {
+	class := q.class(item)
+	if class < 0 || class >= len(q.queues) {
+		return ErrInvalidClass
+	}
+
+	return q.queues[class].Push(item)
+}
+
+// Pop removes and returns the first element of the highest priority non-empty class. If the queue is empty, the
+// ErrQueueEmpty error is returned.
+func (q *ClassQueue[Element]) Pop() (Element, error) {
+	item, err := q.Peek()
+	if err != nil {
+		return item, err
+	}
+
+	return q.first().Pop()
+}
+
+// Peek returns the first element of the highest priority non-empty class. If the queue is empty, the ErrQueueEmpty
+// error is returned.
+func (q *ClassQueue[Element]) Peek() (Element, error) {
+	classQueue := q.first()
+	if classQueue == nil {
+		var item Element
+		return item, ErrQueueEmpty
+	}
+
+	return classQueue.Peek()
+}
+
+// Length returns the number of elements in the queue, across all classes.
+func (q *ClassQueue[Element]) Length() int {
+	length := 0
+	for _, classQueue := range q.queues {
+		length += classQueue.Length()
+	}
+
+	return length
+}
+
+// All returns an iterator over the elements of the queue in the order they would be popped, class by class, without
+// removing them.
+func (q *ClassQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		for _, classQueue := range q.queues {
+			for item := range classQueue.All() {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ClassLengths returns the number of elements in each class, indexed by class.
+func (q *ClassQueue[Element]) ClassLengths() []int {
+	lengths := make([]int, len(q.queues))
+	for i, classQueue := range q.queues {
+		lengths[i] = classQueue.Length()
+	}
+
+	return lengths
+}
+
+// first returns the highest priority non-empty class, or nil if all classes are empty.
+func (q *ClassQueue[Element]) first() Queue[Element] {
+	for _, classQueue := range q.queues {
+		if classQueue.Length() > 0 {
+			return classQueue
+		}
+	}
+
+	return nil
+}
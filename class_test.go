@@ -0,0 +1,47 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassQueue(t *testing.T) {
+	// elements are encoded as class*100 + sequence.
+	class := func(x int) int { return x / 100 }
+
+	t.Run("cannot pop from empty queue", func(t *testing.T) {
+		q := NewClassQueue(3, class)
+
+		_, err := q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, err = q.Peek()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("pops by class priority then push order", func(t *testing.T) {
+		q := NewClassQueue(3, class)
+		for _, x := range []int{201, 101, 1, 202, 2, 102, 3} {
+			assert.NoError(t, q.Push(x))
+		}
+
+		assert.Equal(t, 7, q.Length())
+		assert.Equal(t, []int{3, 2, 2}, q.ClassLengths())
+
+		for _, want := range []int{1, 2, 3, 101, 102, 201, 202} {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x)
+		}
+
+		assert.Equal(t, []int{0, 0, 0}, q.ClassLengths())
+	})
+
+	t.Run("cannot push element with invalid class", func(t *testing.T) {
+		q := NewClassQueue(2, class)
+		assert.ErrorIs(t, q.Push(200), ErrInvalidClass)
+		assert.ErrorIs(t, q.Push(-100), ErrInvalidClass)
+		assert.Equal(t, 0, q.Length())
+	})
+}
@@ -0,0 +1,16 @@
+package queue
+
+import "time"
+
+// Clock provides the current time to queues whose behavior depends on time.
+// Tests can supply their own implementation with the WithClock option to control time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"iter"
+	"time"
+)
+
+// CoalescingQueue is a queue that buffers pushed elements and releases them to Pop in batches, after passing each
+// batch through a combine function that may merge, reorder or drop elements.
+// A batch is released once it holds capacity elements, or once maxDelay has passed since its first element was pushed.
+// The delay is checked whenever the queue is used, so a batch is never released in the background.
+type CoalescingQueue[Element any] struct {
+	pending      []Element
+	pendingSince time.Time
+	ready        Queue[Element]
+	capacity     int
+	maxDelay     time.Duration
+	combine      func([]Element) []Element
+	clock        Clock
+}
+
+var _ Queue[int] = (*CoalescingQueue[int])(nil)
+
+// NewCoalescingQueue returns a new coalescing queue, releasing batches of up to capacity elements through combine.
+// The WithClock option controls the time used to measure maxDelay.
+func NewCoalescingQueue[Element any](capacity int, maxDelay time.Duration, combine func([]Element) []Element, opts ...Option) *CoalescingQueue[Element] {
+	if capacity == 0 {
+		capacity = defaultRingBufferQueueCapacity
+	}
+
+	return &CoalescingQueue[Element]{
+		pending:  make([]Element, 0, capacity),
+		ready:    NewUnboundedQueue[Element](capacity),
+		capacity: capacity,
+		maxDelay: maxDelay,
+		combine:  combine,
+		clock:    newOptions(opts).clock,
+	}
+}
+
+// Push adds an element to the pending batch, releasing the batch if it is full. It always returns nil.
+func (q *CoalescingQueue[Element]) Push(item Element) error {
+	q.flushIfDue()
+
+	if len(q.pending) == 0 {
+		q.pendingSince = q.clock.Now()
+	}
+
+	q.pending = append(q.pending, item)
+	if len(q.pending) >= q.capacity {
+		q.Flush()
+	}
+
+	return nil
+}
+
+// Pop removes and returns the first released element. If no element has been released, the ErrQueueEmpty error is returned.
+func (q *CoalescingQueue[Element]) Pop() (Element, error) {
+	q.flushIfDue()
+	return q.ready.Pop()
+}
+
+// Peek returns the first released element. If no element has been released, the ErrQueueEmpty error is returned.
+func (q *CoalescingQueue[Element]) Peek() (Element, error) {
+	q.flushIfDue()
+	return q.ready.Peek()
+}
+
+// Length returns the number of released elements, which excludes those in the pending batch.
+func (q *CoalescingQueue[Element]) Length() int {
+	q.flushIfDue()
+	return q.ready.Length()
+}
+
+// All returns an iterator over the released elements, from front to back, without removing them.
+// Like Length, it excludes the elements in the pending batch.
+func (q *CoalescingQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		q.flushIfDue()
+
+		for item := range q.ready.All() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// PendingLength returns the number of elements in the pending batch.
+func (q *CoalescingQueue[Element]) PendingLength() int {
+	return len(q.pending)
+}
+
+// Flush releases the pending batch immediately, without waiting for it to fill or for maxDelay to pass.
+func (q *CoalescingQueue[Element]) Flush() {
+	if len(q.pending) == 0 {
+		return
+	}
+
+	for _, item := range q.combine(q.pending) {
+		_ = q.ready.Push(item)
+	}
+
+	clear(q.pending)
+	q.pending = q.pending[:0]
+}
+
+func (q *CoalescingQueue[Element]) flushIfDue() {
+	if len(q.pending) > 0 && q.clock.Now().Sub(q.pendingSince) >= q.maxDelay {
+		q.Flush()
+	}
+}
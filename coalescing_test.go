@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalescingQueue(t *testing.T) {
+	sum := func(items []int) []int {
+		total := 0
+		for _, item := range items {
+			total += item
+		}
+
+		return []int{total}
+	}
+
+	t.Run("pushes within delay are coalesced", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewCoalescingQueue(10, time.Second, sum, WithClock(clock))
+
+		for i := range 4 {
+			assert.NoError(t, q.Push(i+1))
+			clock.Advance(100 * time.Millisecond)
+		}
+
+		assert.Equal(t, 0, q.Length())
+		assert.Equal(t, 4, q.PendingLength())
+		_, err := q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		clock.Advance(time.Second)
+		x, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 10, x)
+		assert.Equal(t, 0, q.PendingLength())
+	})
+
+	t.Run("full batch is released immediately", func(t *testing.T) {
+		q := NewCoalescingQueue(3, time.Hour, sum, WithClock(newFakeClock()))
+
+		for i := range 4 {
+			assert.NoError(t, q.Push(i+1))
+		}
+
+		assert.Equal(t, 1, q.Length())
+		assert.Equal(t, 1, q.PendingLength())
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 6, x)
+	})
+
+	t.Run("delay starts with first pending push", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewCoalescingQueue(10, time.Second, sum, WithClock(clock))
+
+		assert.NoError(t, q.Push(1))
+		clock.Advance(time.Second)
+		assert.NoError(t, q.Push(2))
+		clock.Advance(500 * time.Millisecond)
+
+		x, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+		_, err = q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		clock.Advance(500 * time.Millisecond)
+		x, err = q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+	})
+
+	t.Run("flush releases pending batch", func(t *testing.T) {
+		q := NewCoalescingQueue(10, time.Hour, func(items []int) []int { return items }, WithClock(newFakeClock()))
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		q.Flush()
+		assert.Equal(t, 2, q.Length())
+	})
+}
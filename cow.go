@@ -0,0 +1,97 @@
+package queue
+
+import "iter"
+
+// COWQueue is an unbounded queue that can take cheap snapshots of its contents. A snapshot shares the queue's
+// internal storage, and the queue copies its storage only when it is about to overwrite storage that a snapshot
+// may still be reading.
+type COWQueue[Element any] struct {
+	items  *RingBuffer[Element]
+	shared bool
+}
+
+var _ Queue[int] = (*COWQueue[int])(nil)
+
+// COWSnapshot is an immutable view of the elements a COWQueue held when the snapshot was taken.
+type COWSnapshot[Element any] struct {
+	items  []Element
+	front  int
+	length int
+}
+
+// NewCOWQueue returns a new copy-on-write queue with the specific initial capacity.
+func NewCOWQueue[Element any](initialCapacity int) *COWQueue[Element] {
+	return &COWQueue[Element]{
+		items: NewUnboundedRingBuffer[Element](initialCapacity),
+	}
+}
+
+// Push adds an element to the end of the queue, first copying the queue's storage if a snapshot shares it.
+// It always returns nil.
+func (q *COWQueue[Element]) Push(item Element) error {
+	if q.shared {
+		q.items.resize(q.items.Cap())
+		q.shared = false
+	}
+
+	return q.items.Push(item)
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+// Popping never copies the queue's storage, since it does not overwrite any element.
+func (q *COWQueue[Element]) Pop() (Element, error) {
+	return q.items.Pop()
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *COWQueue[Element]) Peek() (Element, error) {
+	return q.items.Peek()
+}
+
+// Length returns the number of elements in the queue.
+func (q *COWQueue[Element]) Length() int {
+	return q.items.Length()
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them.
+func (q *COWQueue[Element]) All() iter.Seq[Element] {
+	return q.items.All()
+}
+
+// Snapshot returns an immutable view of the current elements of the queue, which is unaffected by later changes.
+func (q *COWQueue[Element]) Snapshot() *COWSnapshot[Element] {
+	q.shared = true
+
+	return &COWSnapshot[Element]{
+		items:  q.items.items,
+		front:  q.items.front,
+		length: q.items.length,
+	}
+}
+
+// Len returns the number of elements in the snapshot.
+func (s *COWSnapshot[Element]) Len() int {
+	return s.length
+}
+
+// At returns the element at index i of the snapshot, where index zero is the front.
+// If i is outside of [0, Len()), the ErrIndexOutOfRange error is returned.
+func (s *COWSnapshot[Element]) At(i int) (Element, error) {
+	if i < 0 || i >= s.length {
+		var item Element
+		return item, ErrIndexOutOfRange
+	}
+
+	return s.items[(s.front+i)%cap(s.items)], nil
+}
+
+// All returns an iterator over the elements of the snapshot, from front to back.
+func (s *COWSnapshot[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		for i := range s.length {
+			if !yield(s.items[(s.front+i)%cap(s.items)]) {
+				return
+			}
+		}
+	}
+}
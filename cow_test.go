@@ -0,0 +1,63 @@
+package queue
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCOWQueue(t *testing.T) {
+	t.Run("snapshot is stable after source is mutated", func(t *testing.T) {
+		q := NewCOWQueue[int](4)
+		for i := range 4 {
+			assert.NoError(t, q.Push(i+1))
+		}
+
+		snapshot := q.Snapshot()
+
+		_, _ = q.Pop()
+		_, _ = q.Pop()
+		assert.NoError(t, q.Push(5))
+		assert.NoError(t, q.Push(6))
+
+		assert.Equal(t, 4, snapshot.Len())
+		assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(snapshot.All()))
+		assert.Equal(t, 4, q.Length())
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, x)
+	})
+
+	t.Run("storage is copied only when shared", func(t *testing.T) {
+		q := NewCOWQueue[int](4)
+		assert.NoError(t, q.Push(1))
+
+		storage := &q.items.items[0]
+		assert.NoError(t, q.Push(2))
+		assert.Same(t, storage, &q.items.items[0])
+
+		q.Snapshot()
+		assert.NoError(t, q.Push(3))
+		assert.NotSame(t, storage, &q.items.items[0])
+
+		storage = &q.items.items[0]
+		assert.NoError(t, q.Push(4))
+		assert.Same(t, storage, &q.items.items[0])
+	})
+
+	t.Run("snapshot elements by index", func(t *testing.T) {
+		q := NewCOWQueue[int](2)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		snapshot := q.Snapshot()
+		x, err := snapshot.At(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+
+		_, err = snapshot.At(2)
+		assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	})
+}
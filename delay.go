@@ -0,0 +1,59 @@
+package queue
+
+import "time"
+
+// DelayQueue is a queue whose elements each become available at a release time given when they are pushed.
+// Elements are popped in order of release time, and elements with equal release times in the order they were pushed.
+type DelayQueue[Element any] struct {
+	heap binaryHeap[delayedElement[Element]]
+	next uint64
+}
+
+type delayedElement[Element any] struct {
+	item      Element
+	releaseAt time.Time
+	seq       uint64
+}
+
+// NewDelayQueue returns a new, empty delay queue.
+func NewDelayQueue[Element any]() *DelayQueue[Element] {
+	return &DelayQueue[Element]{
+		heap: binaryHeap[delayedElement[Element]]{
+			less: func(a, b delayedElement[Element]) bool {
+				if !a.releaseAt.Equal(b.releaseAt) {
+					return a.releaseAt.Before(b.releaseAt)
+				}
+
+				return a.seq < b.seq
+			},
+		},
+	}
+}
+
+// Push adds an element to the queue, to be released at releaseAt.
+func (q *DelayQueue[Element]) Push(item Element, releaseAt time.Time) {
+	q.next++
+	q.heap.push(delayedElement[Element]{item: item, releaseAt: releaseAt, seq: q.next})
+}
+
+// PopReady removes and returns the element with the earliest release time, if that time is not after now.
+// If the queue is empty, the ErrQueueEmpty error is returned. If the earliest release time is after now, the
+// ErrNoneReady error is returned.
+func (q *DelayQueue[Element]) PopReady(now time.Time) (Element, error) {
+	var item Element
+
+	if q.heap.len() == 0 {
+		return item, ErrQueueEmpty
+	}
+
+	if q.heap.items[0].releaseAt.After(now) {
+		return item, ErrNoneReady
+	}
+
+	return q.heap.pop().item, nil
+}
+
+// Length returns the number of elements in the queue, whether or not they are ready for release.
+func (q *DelayQueue[Element]) Length() int {
+	return q.heap.len()
+}
@@ -0,0 +1,48 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelayQueue(t *testing.T) {
+	t.Run("cannot pop from empty queue", func(t *testing.T) {
+		q := NewDelayQueue[string]()
+
+		_, err := q.PopReady(time.Now())
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("only due elements pop in release order", func(t *testing.T) {
+		clock := newFakeClock()
+		start := clock.Now()
+		q := NewDelayQueue[string]()
+
+		q.Push("c", start.Add(3*time.Second))
+		q.Push("a", start.Add(time.Second))
+		q.Push("d", start.Add(5*time.Second))
+		q.Push("b", start.Add(time.Second))
+		assert.Equal(t, 4, q.Length())
+
+		_, err := q.PopReady(clock.Now())
+		assert.ErrorIs(t, err, ErrNoneReady)
+
+		clock.Advance(3 * time.Second)
+		for _, want := range []string{"a", "b", "c"} {
+			x, err := q.PopReady(clock.Now())
+			assert.NoError(t, err)
+			assert.Equal(t, want, x)
+		}
+
+		_, err = q.PopReady(clock.Now())
+		assert.ErrorIs(t, err, ErrNoneReady)
+		assert.Equal(t, 1, q.Length())
+
+		clock.Advance(2 * time.Second)
+		x, err := q.PopReady(clock.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, "d", x)
+	})
+}
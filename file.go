@@ -0,0 +1,275 @@
+package queue
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+)
+
+// Codec converts elements to and from their binary representation.
+type Codec[Element any] interface {
+	// Encode returns the binary representation of an element.
+	Encode(Element) ([]byte, error)
+
+	// Decode returns the element represented by data.
+	Decode(data []byte) (Element, error)
+}
+
+// FileQueue is a queue whose elements are persisted to an append-only file, so that they survive reopening the file.
+// Pushed elements are appended to the file, and popping an element advances a read offset stored at the start of
+// the file. The most recently pushed elements are also kept in a bounded in-memory ring buffer, so that popping them
+// does not read the file; older elements are read back from the file when they reach the front of the queue.
+type FileQueue[Element any] struct {
+	file        *os.File
+	codec       Codec[Element]
+	cache       *RingBuffer[fileQueueEntry[Element]]
+	length      int
+	readOffset  int64
+	writeOffset int64
+}
+
+var _ Queue[int] = (*FileQueue[int])(nil)
+
+type fileQueueEntry[Element any] struct {
+	item Element
+	size int64
+}
+
+const (
+	fileQueueHeaderSize       = 8
+	fileQueueRecordHeaderSize = 4
+	fileQueueCacheCapacity    = 256
+)
+
+// NewFileQueue opens the queue stored in the file at path, creating the file if it does not exist.
+// The elements remaining in an existing file are loaded in their original order.
+// The returned queue is a *FileQueue, whose Sync and Close methods manage the file.
+func NewFileQueue[Element any](path string, codec Codec[Element]) (Queue[Element], error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &FileQueue[Element]{
+		file:  file,
+		codec: codec,
+		cache: NewBoundedQueuePolicy[fileQueueEntry[Element]](fileQueueCacheCapacity, DropOldest),
+	}
+
+	if err := q.load(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return q, nil
+}
+
+func (q *FileQueue[Element]) load() error {
+	info, err := q.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() == 0 {
+		return q.reset()
+	}
+
+	var header [fileQueueHeaderSize]byte
+	if _, err := q.file.ReadAt(header[:], 0); err != nil {
+		return fmt.Errorf("reading queue file header: %w", err)
+	}
+
+	q.readOffset = int64(binary.BigEndian.Uint64(header[:]))
+	q.writeOffset = info.Size()
+
+	if q.readOffset < fileQueueHeaderSize || q.readOffset > q.writeOffset {
+		return fmt.Errorf("queue file read offset %d is outside of [%d, %d]", q.readOffset, fileQueueHeaderSize, q.writeOffset)
+	}
+
+	for offset := q.readOffset; offset < q.writeOffset; {
+		entry, err := q.readRecord(offset)
+		if err != nil {
+			return err
+		}
+
+		_ = q.cache.Push(entry)
+		q.length++
+		offset += entry.size
+	}
+
+	return nil
+}
+
+// readRecord reads and decodes the record at offset in the file.
+func (q *FileQueue[Element]) readRecord(offset int64) (fileQueueEntry[Element], error) {
+	var entry fileQueueEntry[Element]
+
+	var recordHeader [fileQueueRecordHeaderSize]byte
+	if _, err := q.file.ReadAt(recordHeader[:], offset); err != nil {
+		return entry, fmt.Errorf("reading queue file record at offset %d: %w", offset, noEOF(err))
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(recordHeader[:]))
+	if _, err := q.file.ReadAt(data, offset+fileQueueRecordHeaderSize); err != nil {
+		return entry, fmt.Errorf("reading queue file record at offset %d: %w", offset, noEOF(err))
+	}
+
+	item, err := q.codec.Decode(data)
+	if err != nil {
+		return entry, fmt.Errorf("decoding queue file record at offset %d: %w", offset, err)
+	}
+
+	return fileQueueEntry[Element]{item: item, size: int64(fileQueueRecordHeaderSize + len(data))}, nil
+}
+
+// noEOF reports a record cut short by the end of the file as an unexpected end of file.
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// reset discards the contents of the file, leaving only a header pointing at the end of the file.
+// The header is written first, so that the file never holds a read offset beyond its end: a crash before the file is
+// truncated redelivers the discarded elements rather than losing later pushes.
+func (q *FileQueue[Element]) reset() error {
+	if err := q.writeReadOffset(fileQueueHeaderSize); err != nil {
+		return err
+	}
+
+	if err := q.file.Truncate(fileQueueHeaderSize); err != nil {
+		return err
+	}
+
+	q.writeOffset = fileQueueHeaderSize
+
+	return nil
+}
+
+func (q *FileQueue[Element]) writeReadOffset(offset int64) error {
+	var header [fileQueueHeaderSize]byte
+	binary.BigEndian.PutUint64(header[:], uint64(offset))
+
+	if _, err := q.file.WriteAt(header[:], 0); err != nil {
+		return err
+	}
+
+	q.readOffset = offset
+
+	return nil
+}
+
+// Push encodes an element and appends it to the end of the queue and its file.
+// If encoding or writing fails, the error is returned and the queue is unchanged.
+func (q *FileQueue[Element]) Push(item Element) error {
+	data, err := q.codec.Encode(item)
+	if err != nil {
+		return err
+	}
+
+	record := make([]byte, fileQueueRecordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	copy(record[fileQueueRecordHeaderSize:], data)
+
+	if _, err := q.file.WriteAt(record, q.writeOffset); err != nil {
+		return err
+	}
+
+	q.writeOffset += int64(len(record))
+	q.length++
+
+	// a full cache drops its oldest element, which stays in the file.
+	return q.cache.Push(fileQueueEntry[Element]{item: item, size: int64(len(record))})
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+// If the element cannot be read from the file or the read offset cannot be written to it, the error is returned and
+// the queue is unchanged.
+func (q *FileQueue[Element]) Pop() (Element, error) {
+	entry, err := q.front()
+	if err != nil {
+		return entry.item, err
+	}
+
+	if q.length == 1 {
+		// nothing is left to read, so the file can be emptied rather than grow indefinitely.
+		err = q.reset()
+	} else {
+		err = q.writeReadOffset(q.readOffset + entry.size)
+	}
+
+	if err != nil {
+		var empty Element
+		return empty, err
+	}
+
+	if q.cache.Length() == q.length {
+		_, _ = q.cache.Pop()
+	}
+
+	q.length--
+
+	return entry.item, nil
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+// If the element cannot be read from the file, the error is returned.
+func (q *FileQueue[Element]) Peek() (Element, error) {
+	entry, err := q.front()
+	return entry.item, err
+}
+
+// front returns the first entry of the queue, reading it from the file if it is older than the cached elements.
+func (q *FileQueue[Element]) front() (fileQueueEntry[Element], error) {
+	if q.length == 0 {
+		return fileQueueEntry[Element]{}, ErrQueueEmpty
+	}
+
+	if q.cache.Length() == q.length {
+		return q.cache.Peek()
+	}
+
+	return q.readRecord(q.readOffset)
+}
+
+// Length returns the number of elements in the queue.
+func (q *FileQueue[Element]) Length() int {
+	return q.length
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them.
+// Elements older than the cached elements are read from the file, and iteration stops early if one cannot be read.
+func (q *FileQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		offset := q.readOffset
+		for range q.length - q.cache.Length() {
+			entry, err := q.readRecord(offset)
+			if err != nil || !yield(entry.item) {
+				return
+			}
+
+			offset += entry.size
+		}
+
+		for entry := range q.cache.All() {
+			if !yield(entry.item) {
+				return
+			}
+		}
+	}
+}
+
+// Sync commits the contents of the queue's file to stable storage.
+func (q *FileQueue[Element]) Sync() error {
+	return q.file.Sync()
+}
+
+// Close closes the queue's file. The queue must not be used after it is closed.
+func (q *FileQueue[Element]) Close() error {
+	return q.file.Close()
+}
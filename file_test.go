@@ -0,0 +1,185 @@
+package queue
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type intCodec struct{}
+
+func (intCodec) Encode(x int) ([]byte, error) {
+	if x < 0 {
+		return nil, errors.New("negative values are not supported")
+	}
+
+	return []byte(strconv.Itoa(x)), nil
+}
+
+func (intCodec) Decode(data []byte) (int, error) {
+	return strconv.Atoi(string(data))
+}
+
+func TestFileQueue(t *testing.T) {
+	open := func(t *testing.T, path string) *FileQueue[int] {
+		t.Helper()
+
+		q, err := NewFileQueue[int](path, intCodec{})
+		require.NoError(t, err)
+
+		fq := q.(*FileQueue[int])
+		t.Cleanup(func() { _ = fq.Close() })
+
+		return fq
+	}
+
+	t.Run("push and pop in order", func(t *testing.T) {
+		q := open(t, filepath.Join(t.TempDir(), "queue"))
+		assert.Equal(t, 0, q.Length())
+
+		_, err := q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		for i := range 3 {
+			assert.NoError(t, q.Push(i+1))
+		}
+		assert.Equal(t, 3, q.Length())
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+
+		for i := range 3 {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, i+1, x)
+		}
+	})
+
+	t.Run("elements survive reopen", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue")
+
+		q := open(t, path)
+		for i := range 5 {
+			assert.NoError(t, q.Push(i*10))
+		}
+
+		_, err := q.Pop()
+		assert.NoError(t, err)
+		assert.NoError(t, q.Sync())
+		assert.NoError(t, q.Close())
+
+		q = open(t, path)
+		assert.Equal(t, 4, q.Length())
+		assert.NoError(t, q.Push(50))
+		assert.NoError(t, q.Close())
+
+		q = open(t, path)
+		for i := range 5 {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, (i+1)*10, x)
+		}
+	})
+
+	t.Run("elements beyond the cache are read from the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue")
+		n := fileQueueCacheCapacity + 10
+
+		q := open(t, path)
+		for i := range n {
+			assert.NoError(t, q.Push(i))
+		}
+		assert.Equal(t, n, q.Length())
+		assert.Equal(t, fileQueueCacheCapacity, q.cache.Length())
+
+		var all []int
+		for x := range q.All() {
+			all = append(all, x)
+		}
+		assert.Len(t, all, n)
+		assert.Equal(t, 0, all[0])
+		assert.Equal(t, n-1, all[n-1])
+
+		for i := range 5 {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, i, x)
+		}
+		assert.NoError(t, q.Close())
+
+		q = open(t, path)
+		assert.Equal(t, n-5, q.Length())
+		for i := 5; i < n; i++ {
+			x, err := q.Peek()
+			assert.NoError(t, err)
+			assert.Equal(t, i, x)
+
+			x, err = q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, i, x)
+		}
+		assert.Equal(t, 0, q.Length())
+	})
+
+	t.Run("file is emptied once all elements are popped", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue")
+
+		q := open(t, path)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+		_, _ = q.Pop()
+		_, _ = q.Pop()
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(fileQueueHeaderSize), info.Size())
+	})
+
+	t.Run("encode failure leaves queue unchanged", func(t *testing.T) {
+		q := open(t, filepath.Join(t.TempDir(), "queue"))
+		assert.Error(t, q.Push(-1))
+		assert.Equal(t, 0, q.Length())
+	})
+
+	t.Run("truncated record is reported", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue")
+
+		q := open(t, path)
+		assert.NoError(t, q.Push(12345))
+		assert.NoError(t, q.Close())
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, os.Truncate(path, info.Size()-1))
+
+		_, err = NewFileQueue[int](path, intCodec{})
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("read offset outside of the file is reported", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue")
+
+		q := open(t, path)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Close())
+
+		var header [fileQueueHeaderSize]byte
+		binary.BigEndian.PutUint64(header[:], 1<<20)
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		require.NoError(t, err)
+		_, err = file.WriteAt(header[:], 0)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		_, err = NewFileQueue[int](path, intCodec{})
+		assert.ErrorContains(t, err, "read offset")
+	})
+}
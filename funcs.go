@@ -0,0 +1,306 @@
+package queue
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"slices"
+)
+
+// Pair holds two values, such as corresponding elements of two queues.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Number is a constraint satisfied by the integer and floating-point types.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// DistinctCount returns the number of distinct elements in q, without modifying it.
+func DistinctCount[Element comparable](q Queue[Element]) int {
+	seen := make(map[Element]struct{}, q.Length())
+	for item := range q.All() {
+		seen[item] = struct{}{}
+	}
+
+	return len(seen)
+}
+
+// FindDuplicates returns the distinct elements that appear more than once in q, in the order that their second
+// occurrences appear, without modifying q. It returns nil if every element is distinct.
+func FindDuplicates[Element comparable](q Queue[Element]) []Element {
+	var duplicates []Element
+	counts := make(map[Element]int, q.Length())
+	for item := range q.All() {
+		counts[item]++
+		if counts[item] == 2 {
+			duplicates = append(duplicates, item)
+		}
+	}
+
+	return duplicates
+}
+
+// Diff compares the elements of two queues as multisets, ignoring their order. It returns the elements of after that
+// are not matched by an equal element of before, in their order in after, and the elements of before that are not
+// matched by an equal element of after, in their order in before. Neither queue is modified.
+func Diff[Element comparable](before, after Queue[Element]) (added, removed []Element) {
+	unmatched := make(map[Element]int, before.Length())
+	for item := range before.All() {
+		unmatched[item]++
+	}
+
+	for item := range after.All() {
+		if unmatched[item] > 0 {
+			unmatched[item]--
+		} else {
+			added = append(added, item)
+		}
+	}
+
+	for item := range before.All() {
+		if unmatched[item] > 0 {
+			unmatched[item]--
+			removed = append(removed, item)
+		}
+	}
+
+	return added, removed
+}
+
+// EqualSlice reports whether the elements of q, in order, are equal to want.
+func EqualSlice[Element comparable](q Queue[Element], want []Element) bool {
+	return q.Length() == len(want) && HasPrefix(q, want)
+}
+
+// Any reports whether pred returns true for at least one element of q, checking elements in order and stopping at
+// the first match.
+func Any[Element any](q Queue[Element], pred func(Element) bool) bool {
+	for item := range q.All() {
+		if pred(item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// All reports whether pred returns true for every element of q, checking elements in order and stopping at the
+// first element that does not match. It returns true for an empty queue.
+func All[Element any](q Queue[Element], pred func(Element) bool) bool {
+	for item := range q.All() {
+		if !pred(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Partition returns the elements of q for which pred returns true and those for which it returns false, each in
+// their order in q, without modifying q.
+func Partition[Element any](q Queue[Element], pred func(Element) bool) (yes, no []Element) {
+	for item := range q.All() {
+		if pred(item) {
+			yes = append(yes, item)
+		} else {
+			no = append(no, item)
+		}
+	}
+
+	return yes, no
+}
+
+// HasPrefix reports whether the first len(prefix) elements of q, in order, are equal to prefix.
+// It returns false if prefix is longer than q.
+func HasPrefix[Element comparable](q Queue[Element], prefix []Element) bool {
+	if len(prefix) > q.Length() {
+		return false
+	}
+
+	i := 0
+	for item := range q.All() {
+		if i == len(prefix) {
+			break
+		}
+
+		if item != prefix[i] {
+			return false
+		}
+		i++
+	}
+
+	return true
+}
+
+// Coalesce moves the elements of each queue in queues, in slice order, to the end of into, and returns the total
+// number of elements moved. If into cannot accept more elements, coalescing stops and the ErrQueueFull error is
+// returned, leaving the remaining elements in their source queues.
+func Coalesce[Element any](queues []Queue[Element], into Queue[Element]) (int, error) {
+	total := 0
+	for _, src := range queues {
+		for src.Length() > 0 {
+			item, _ := src.Peek()
+			if err := into.Push(item); err != nil {
+				return total, err
+			}
+
+			_, _ = src.Pop()
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+// Shard pops every element of q and distributes them in turn across n new unbounded queues, starting with the first,
+// so that the shards differ in length by at most one and each holds its elements in their order in q.
+// Shard panics if n is less than one.
+func Shard[Element any](q Queue[Element], n int) []Queue[Element] {
+	if n < 1 {
+		panic("queue: shard count must be at least one")
+	}
+
+	shards := make([]Queue[Element], n)
+	for i := range shards {
+		shards[i] = NewUnboundedQueue[Element]((q.Length() + n - 1) / n)
+	}
+
+	for i := 0; q.Length() > 0; i = (i + 1) % n {
+		item, _ := q.Pop()
+		_ = shards[i].Push(item)
+	}
+
+	return shards
+}
+
+// Zip returns a new unbounded queue pairing the elements of a and b by position, in order. The new queue is as long
+// as the shorter of a and b, whose remaining elements are ignored. Both a and b are left unchanged.
+func Zip[A, B any](a Queue[A], b Queue[B]) Queue[Pair[A, B]] {
+	zipped := NewUnboundedQueue[Pair[A, B]](min(a.Length(), b.Length()))
+
+	next, stop := iter.Pull(b.All())
+	defer stop()
+
+	for first := range a.All() {
+		second, ok := next()
+		if !ok {
+			break
+		}
+
+		_ = zipped.Push(Pair[A, B]{First: first, Second: second})
+	}
+
+	return zipped
+}
+
+// Sum returns the sum of the elements of q, without modifying it. It returns zero for an empty queue.
+func Sum[Element Number](q Queue[Element]) Element {
+	var sum Element
+	for item := range q.All() {
+		sum += item
+	}
+
+	return sum
+}
+
+// Average returns the arithmetic mean of the elements of q, without modifying it. It returns zero for an empty queue.
+func Average[Element Number](q Queue[Element]) float64 {
+	if q.Length() == 0 {
+		return 0
+	}
+
+	// summing in float64 avoids overflowing narrow element types.
+	var sum float64
+	for item := range q.All() {
+		sum += float64(item)
+	}
+
+	return sum / float64(q.Length())
+}
+
+// CountInRange returns the number of elements of q that are at least lo and at most hi, without modifying q.
+func CountInRange[Element cmp.Ordered](q Queue[Element], lo, hi Element) int {
+	count := 0
+	for item := range q.All() {
+		if lo <= item && item <= hi {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Clamp replaces each element of q that is less than lo with lo, and each that is greater than hi with hi, keeping the
+// order of the elements. A ring buffer is updated in place; any other queue has each element popped and pushed back in
+// turn, so q ends up holding the same number of elements.
+func Clamp[Element cmp.Ordered](q Queue[Element], lo, hi Element) {
+	if rb, ok := q.(*RingBuffer[Element]); ok {
+		rb.UpdateEach(func(item *Element) {
+			*item = min(max(*item, lo), hi)
+		})
+
+		return
+	}
+
+	for range q.Length() {
+		item, _ := q.Pop()
+		_ = q.Push(min(max(item, lo), hi))
+	}
+}
+
+// Percentile returns the p-th percentile of the elements of q, for p from 0 to 100, interpolating linearly between the
+// two nearest elements when the percentile falls between them. Values of p outside that range are treated as the
+// nearest end of it. The elements are copied and sorted, leaving q unchanged. If q is empty, the ErrQueueEmpty error is
+// returned. If p is NaN, the ErrInvalidPercentile error is returned.
+func Percentile[Element Number](q Queue[Element], p float64) (float64, error) {
+	if math.IsNaN(p) {
+		return 0, ErrInvalidPercentile
+	}
+
+	if q.Length() == 0 {
+		return 0, ErrQueueEmpty
+	}
+
+	sorted := slices.Sorted(q.All())
+	rank := min(max(p, 0), 100) / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower == len(sorted)-1 {
+		return float64(sorted[lower]), nil
+	}
+
+	frac := rank - float64(lower)
+
+	return float64(sorted[lower]) + frac*(float64(sorted[lower+1])-float64(sorted[lower])), nil
+}
+
+// MergeSorted returns a new unbounded queue holding the elements of a and b, merged in order as determined by the
+// less function. Both a and b must already be in that order, and are left unchanged. Of two equal elements, the one
+// from a comes first.
+func MergeSorted[Element any](a, b Queue[Element], less func(x, y Element) bool) Queue[Element] {
+	merged := NewUnboundedQueue[Element](a.Length() + b.Length())
+
+	nextA, stopA := iter.Pull(a.All())
+	defer stopA()
+
+	nextB, stopB := iter.Pull(b.All())
+	defer stopB()
+
+	x, okA := nextA()
+	y, okB := nextB()
+	for okA || okB {
+		if okA && (!okB || !less(y, x)) {
+			_ = merged.Push(x)
+			x, okA = nextA()
+		} else {
+			_ = merged.Push(y)
+			y, okB = nextB()
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,280 @@
+package queue
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createWrappedUnboundedQueue(t *testing.T, items ...int) *RingBuffer[int] {
+	t.Helper()
+
+	return createWrappedQueue(t, func(capacity int) *RingBuffer[int] {
+		return NewUnboundedRingBuffer[int](capacity)
+	}, items...)
+}
+
+func TestDistinctCount(t *testing.T) {
+	assert.Equal(t, 0, DistinctCount(NewUnboundedQueue[int](1)))
+
+	q := createWrappedUnboundedQueue(t, 1, 2, 3, 1, 2, 4)
+	assert.Equal(t, 4, DistinctCount(q))
+	assert.Equal(t, 6, q.Length())
+}
+
+func TestFindDuplicates(t *testing.T) {
+	assert.Nil(t, FindDuplicates(createWrappedUnboundedQueue(t, 1, 2, 3)))
+
+	q := createWrappedUnboundedQueue(t, 4, 1, 2, 3, 2, 4, 1, 2)
+	assert.Equal(t, []int{2, 4, 1}, FindDuplicates(q))
+	assert.Equal(t, 8, q.Length())
+}
+
+func TestDiff(t *testing.T) {
+	before := createWrappedUnboundedQueue(t, 1, 2, 2, 3, 4)
+	after := NewUnboundedQueueFrom(before)
+
+	for range 2 {
+		_, err := after.Pop()
+		assert.NoError(t, err)
+	}
+
+	for _, x := range []int{5, 2, 6} {
+		assert.NoError(t, after.Push(x))
+	}
+
+	added, removed := Diff(before, after)
+	assert.Equal(t, []int{5, 6}, added)
+	assert.Equal(t, []int{1}, removed)
+	assert.Equal(t, 5, before.Length())
+	assert.Equal(t, 6, after.Length())
+
+	added, removed = Diff(before, before)
+	assert.Nil(t, added)
+	assert.Nil(t, removed)
+}
+
+func TestEqualSlice(t *testing.T) {
+	q := createWrappedUnboundedQueue(t, 1, 2, 3, 4, 5)
+
+	assert.True(t, EqualSlice(q, []int{1, 2, 3, 4, 5}))
+	assert.False(t, EqualSlice(q, []int{1, 2, 3, 4}))
+	assert.False(t, EqualSlice(q, []int{1, 2, 3, 4, 5, 6}))
+	assert.False(t, EqualSlice(q, []int{1, 2, 3, 9, 5}))
+	assert.True(t, EqualSlice(NewUnboundedQueue[int](1), nil))
+}
+
+func TestAny(t *testing.T) {
+	q := createWrappedUnboundedQueue(t, 1, 3, 5, 7, 8, 9)
+
+	assert.True(t, Any(q, func(x int) bool { return x%2 == 0 }))
+	assert.False(t, Any(q, func(x int) bool { return x > 10 }))
+	assert.False(t, Any(NewUnboundedQueue[int](1), func(int) bool { return true }))
+}
+
+func TestAll(t *testing.T) {
+	q := createWrappedUnboundedQueue(t, 1, 3, 5, 7, 8, 9)
+
+	var checked []int
+	assert.False(t, All(q, func(x int) bool {
+		checked = append(checked, x)
+		return x%2 == 1
+	}))
+	assert.Equal(t, []int{1, 3, 5, 7, 8}, checked)
+
+	assert.True(t, All(q, func(x int) bool { return x < 10 }))
+	assert.True(t, All(NewUnboundedQueue[int](1), func(int) bool { return false }))
+}
+
+func TestPartition(t *testing.T) {
+	q := createWrappedUnboundedQueue(t, 5, 1, 8, 3, 9, 2)
+
+	yes, no := Partition(q, func(x int) bool { return x >= 5 })
+	assert.Equal(t, []int{5, 8, 9}, yes)
+	assert.Equal(t, []int{1, 3, 2}, no)
+	assert.Equal(t, 6, q.Length())
+
+	yes, no = Partition(NewUnboundedQueue[int](1), func(x int) bool { return true })
+	assert.Nil(t, yes)
+	assert.Nil(t, no)
+}
+
+func TestHasPrefix(t *testing.T) {
+	q := createWrappedUnboundedQueue(t, 1, 2, 3, 4, 5)
+
+	assert.True(t, HasPrefix(q, []int{1, 2, 3, 4}))
+	assert.True(t, HasPrefix(q, []int{1, 2, 3, 4, 5}))
+	assert.True(t, HasPrefix(q, nil))
+	assert.False(t, HasPrefix(q, []int{1, 2, 4}))
+	assert.False(t, HasPrefix(q, []int{1, 2, 3, 4, 5, 6}))
+	assert.Equal(t, 5, q.Length())
+}
+
+func TestCoalesce(t *testing.T) {
+	t.Run("drains sources in order", func(t *testing.T) {
+		sources := []Queue[int]{
+			createWrappedUnboundedQueue(t, 1, 2),
+			createWrappedUnboundedQueue(t, 3),
+			createWrappedUnboundedQueue(t, 4, 5, 6),
+		}
+		into := NewBoundedQueue[int](8)
+
+		count, err := Coalesce(sources, into)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, count)
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, slices.Collect(into.All()))
+
+		for _, src := range sources {
+			assert.Equal(t, 0, src.Length())
+		}
+	})
+
+	t.Run("stops when destination is full", func(t *testing.T) {
+		sources := []Queue[int]{
+			createWrappedUnboundedQueue(t, 1, 2),
+			createWrappedUnboundedQueue(t, 3, 4),
+			createWrappedUnboundedQueue(t, 5),
+		}
+		into := NewBoundedQueue[int](3)
+
+		count, err := Coalesce(sources, into)
+		assert.ErrorIs(t, err, ErrQueueFull)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(into.All()))
+		assert.Equal(t, []int{4}, slices.Collect(sources[1].All()))
+		assert.Equal(t, 1, sources[2].Length())
+	})
+}
+
+func TestShard(t *testing.T) {
+	q := createWrappedUnboundedQueue(t, 1, 2, 3, 4, 5, 6, 7)
+
+	shards := Shard(q, 3)
+	assert.Len(t, shards, 3)
+	assert.Equal(t, []int{1, 4, 7}, slices.Collect(shards[0].All()))
+	assert.Equal(t, []int{2, 5}, slices.Collect(shards[1].All()))
+	assert.Equal(t, []int{3, 6}, slices.Collect(shards[2].All()))
+	assert.Equal(t, 0, q.Length())
+
+	assert.Panics(t, func() { Shard(q, 0) })
+}
+
+func TestZip(t *testing.T) {
+	a := createWrappedUnboundedQueue(t, 1, 2, 3, 4)
+	b := NewUnboundedQueue[string](3)
+	for _, s := range []string{"a", "b", "c"} {
+		assert.NoError(t, b.Push(s))
+	}
+
+	want := []Pair[int, string]{{1, "a"}, {2, "b"}, {3, "c"}}
+	assert.Equal(t, want, slices.Collect(Zip(a, b).All()))
+	assert.Equal(t, []Pair[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}, slices.Collect(Zip(b, a).All()))
+	assert.Equal(t, 4, a.Length())
+	assert.Equal(t, 3, b.Length())
+
+	assert.Equal(t, 0, Zip(a, NewUnboundedQueue[string](1)).Length())
+}
+
+func TestSum(t *testing.T) {
+	assert.Equal(t, 0, Sum(NewUnboundedQueue[int](1)))
+	assert.Equal(t, 15, Sum(createWrappedUnboundedQueue(t, 1, 2, 3, 4, 5)))
+
+	floats := NewUnboundedQueue[float64](2)
+	assert.NoError(t, floats.Push(1.5))
+	assert.NoError(t, floats.Push(2.25))
+	assert.Equal(t, 3.75, Sum(floats))
+}
+
+func TestAverage(t *testing.T) {
+	assert.Equal(t, 0.0, Average(NewUnboundedQueue[int](1)))
+
+	q := createWrappedUnboundedQueue(t, 1, 2, 3, 4)
+	assert.Equal(t, 2.5, Average[int](q))
+	assert.Equal(t, 4, q.Length())
+
+	small := NewUnboundedQueue[int8](2)
+	assert.NoError(t, small.Push(100))
+	assert.NoError(t, small.Push(100))
+	assert.Equal(t, 100.0, Average(small))
+}
+
+func TestCountInRange(t *testing.T) {
+	q := createWrappedUnboundedQueue(t, 5, -2, 10, 3, 7, 11, 0)
+
+	assert.Equal(t, 4, CountInRange(q, 0, 7))
+	assert.Equal(t, 0, CountInRange(q, 20, 30))
+	assert.Equal(t, 0, CountInRange(q, 7, 0))
+	assert.Equal(t, 7, q.Length())
+}
+
+func TestClamp(t *testing.T) {
+	q := createWrappedUnboundedQueue(t, -5, 3, 12, 0, 7, 10)
+	front := q.front
+	Clamp(q, 0, 10)
+	assert.Equal(t, []int{0, 3, 10, 0, 7, 10}, slices.Collect(q.All()))
+	assert.Equal(t, front, q.front)
+
+	bounded := createWrappedQueue(t, func(capacity int) *RingBuffer[int] {
+		return NewBoundedRingBuffer[int](capacity)
+	}, 9, 1, 5)
+	Clamp(bounded, 2, 6)
+	assert.Equal(t, []int{6, 2, 5}, slices.Collect(bounded.All()))
+
+	wrapped := NewAtomicLengthQueue(NewUnboundedQueue[int](3))
+	for _, x := range []int{-1, 4, 9} {
+		assert.NoError(t, wrapped.Push(x))
+	}
+	Clamp(wrapped, 0, 5)
+	assert.Equal(t, []int{0, 4, 5}, slices.Collect(wrapped.All()))
+}
+
+func TestPercentile(t *testing.T) {
+	_, err := Percentile(NewUnboundedQueue[int](1), 50)
+	assert.ErrorIs(t, err, ErrQueueEmpty)
+
+	q := createWrappedUnboundedQueue(t, 50, 10, 40, 20, 30)
+
+	for _, tc := range []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 10},
+		{p: 50, want: 30},
+		{p: 90, want: 46},
+		{p: 100, want: 50},
+		{p: 150, want: 50},
+	} {
+		got, err := Percentile(q, tc.p)
+		assert.NoError(t, err)
+		assert.InDelta(t, tc.want, got, 1e-9, "p%v", tc.p)
+	}
+
+	assert.Equal(t, []int{50, 10, 40, 20, 30}, slices.Collect(q.All()))
+
+	even := createWrappedUnboundedQueue(t, 4, 1, 3, 2)
+	median, err := Percentile(even, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, median)
+
+	_, err = Percentile(even, math.NaN())
+	assert.ErrorIs(t, err, ErrInvalidPercentile)
+}
+
+func TestMergeSorted(t *testing.T) {
+	less := func(x, y int) bool { return x < y }
+
+	a := createWrappedUnboundedQueue(t, 1, 3, 5)
+	b := createWrappedUnboundedQueue(t, 2, 4, 6)
+
+	merged := MergeSorted(a, b, less)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, slices.Collect(merged.All()))
+	assert.Equal(t, []int{1, 3, 5}, slices.Collect(a.All()))
+	assert.Equal(t, []int{2, 4, 6}, slices.Collect(b.All()))
+
+	uneven := MergeSorted(createWrappedUnboundedQueue(t, 2, 2, 9), createWrappedUnboundedQueue(t, 1, 2), less)
+	assert.Equal(t, []int{1, 2, 2, 2, 9}, slices.Collect(uneven.All()))
+
+	assert.Equal(t, 0, MergeSorted(NewUnboundedQueue[int](1), NewUnboundedQueue[int](1), less).Length())
+}
@@ -0,0 +1,44 @@
+package queue
+
+// GrowthPolicy decides how much an unbounded queue's internal storage grows when it must resize.
+type GrowthPolicy interface {
+	// NextCapacity returns the new capacity for storage of the current capacity that must hold at least needed
+	// elements. A result smaller than needed is treated as needed.
+	NextCapacity(current, needed int) int
+}
+
+// DoublingPolicy is a GrowthPolicy that doubles the capacity until it is large enough.
+// It is the policy used by NewUnboundedQueue.
+type DoublingPolicy struct{}
+
+func (DoublingPolicy) NextCapacity(current, needed int) int {
+	capacity := max(current, 1)
+	for capacity < needed {
+		capacity *= 2
+	}
+
+	return capacity
+}
+
+// IncrementPolicy is a GrowthPolicy that grows the capacity by a fixed step until it is large enough.
+// A step of zero or less grows the capacity to exactly the needed size.
+type IncrementPolicy int
+
+func (step IncrementPolicy) NextCapacity(current, needed int) int {
+	if step <= 0 || current >= needed {
+		return needed
+	}
+
+	steps := (needed - current + int(step) - 1) / int(step)
+
+	return current + steps*int(step)
+}
+
+// NewUnboundedQueueWithPolicy returns a new unbounded queue with the specific initial capacity, which resizes its
+// internal storage as determined by policy.
+func NewUnboundedQueueWithPolicy[Element any](initialCapacity int, policy GrowthPolicy, opts ...Option) *RingBuffer[Element] {
+	q := NewUnboundedRingBuffer[Element](initialCapacity, opts...)
+	q.growth = policy
+
+	return q
+}
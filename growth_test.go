@@ -0,0 +1,58 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrowthPolicy(t *testing.T) {
+	capacities := func(q *RingBuffer[int], pushes int) []int {
+		result := []int{q.Cap()}
+		for i := range pushes {
+			assert.NoError(t, q.Push(i))
+			if q.Cap() != result[len(result)-1] {
+				result = append(result, q.Cap())
+			}
+		}
+
+		return result
+	}
+
+	t.Run("doubling", func(t *testing.T) {
+		q := NewUnboundedQueueWithPolicy[int](2, DoublingPolicy{})
+		assert.Equal(t, []int{2, 4, 8, 16}, capacities(q, 9))
+
+		assert.Equal(t, 16, DoublingPolicy{}.NextCapacity(4, 13))
+		assert.Equal(t, 1, DoublingPolicy{}.NextCapacity(0, 1))
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		q := NewUnboundedQueueWithPolicy[int](2, IncrementPolicy(3))
+		assert.Equal(t, []int{2, 5, 8, 11}, capacities(q, 9))
+
+		assert.Equal(t, 10, IncrementPolicy(3).NextCapacity(4, 9))
+		assert.Equal(t, 9, IncrementPolicy(0).NextCapacity(4, 9))
+	})
+
+	t.Run("split queues keep policy", func(t *testing.T) {
+		q := NewUnboundedQueueWithPolicy[int](2, IncrementPolicy(1))
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		front, back, err := q.SplitAt(1)
+		assert.NoError(t, err)
+
+		for _, half := range []*RingBuffer[int]{front, back} {
+			assert.NoError(t, half.Push(3))
+			assert.NoError(t, half.Push(4))
+			assert.Equal(t, 3, half.Cap())
+		}
+	})
+
+	t.Run("policy result below needed is raised", func(t *testing.T) {
+		q := NewUnboundedQueueWithPolicy[int](2, IncrementPolicy(1))
+		assert.NoError(t, q.PushAllAtomic(1, 2, 3, 4, 5))
+		assert.Equal(t, 5, q.Cap())
+	})
+}
@@ -0,0 +1,60 @@
+package queue
+
+// binaryHeap is a binary min-heap of elements ordered by a less function.
+type binaryHeap[Element any] struct {
+	items []Element
+	less  func(a, b Element) bool
+}
+
+func (h *binaryHeap[Element]) len() int {
+	return len(h.items)
+}
+
+func (h *binaryHeap[Element]) push(item Element) {
+	h.items = append(h.items, item)
+	h.up(len(h.items) - 1)
+}
+
+// pop removes and returns the least element. The heap must not be empty.
+func (h *binaryHeap[Element]) pop() Element {
+	var empty Element
+
+	last := len(h.items) - 1
+	item := h.items[0]
+	h.items[0] = h.items[last]
+	h.items[last] = empty
+	h.items = h.items[:last]
+	h.down(0)
+
+	return item
+}
+
+func (h *binaryHeap[Element]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.items[i], h.items[parent]) {
+			return
+		}
+
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+func (h *binaryHeap[Element]) down(i int) {
+	for {
+		least := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(h.items) && h.less(h.items[child], h.items[least]) {
+				least = child
+			}
+		}
+
+		if least == i {
+			return
+		}
+
+		h.items[i], h.items[least] = h.items[least], h.items[i]
+		i = least
+	}
+}
@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"fmt"
+	"iter"
+)
+
+// IdentifiedQueue is an unbounded queue that assigns each pushed element an ID, for correlating an element's progress
+// through the queue, such as in logs. IDs are formatted like UUIDs and increase with each push, so that they sort in
+// the order the elements were pushed.
+type IdentifiedQueue[Element any] struct {
+	items Queue[identifiedElement[Element]]
+	next  uint64
+}
+
+var _ Queue[int] = (*IdentifiedQueue[int])(nil)
+
+type identifiedElement[Element any] struct {
+	item Element
+	id   string
+}
+
+// NewIdentifiedQueue returns a new identified queue with the specific initial capacity.
+func NewIdentifiedQueue[Element any](initialCapacity int) *IdentifiedQueue[Element] {
+	return &IdentifiedQueue[Element]{
+		items: NewUnboundedQueue[identifiedElement[Element]](initialCapacity),
+	}
+}
+
+// Push adds an element to the end of the queue. It always returns nil.
+func (q *IdentifiedQueue[Element]) Push(item Element) error {
+	_ = q.PushID(item)
+	return nil
+}
+
+// PushID adds an element to the end of the queue and returns the ID assigned to it.
+func (q *IdentifiedQueue[Element]) PushID(item Element) string {
+	q.next++
+	id := fmt.Sprintf("00000000-0000-0000-%04x-%012x", q.next>>48, q.next&(1<<48-1))
+	_ = q.items.Push(identifiedElement[Element]{item: item, id: id})
+
+	return id
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *IdentifiedQueue[Element]) Pop() (Element, error) {
+	item, _, err := q.PopID()
+	return item, err
+}
+
+// PopID removes and returns the first element of the queue along with the ID assigned when it was pushed.
+// If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *IdentifiedQueue[Element]) PopID() (Element, string, error) {
+	entry, err := q.items.Pop()
+	return entry.item, entry.id, err
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *IdentifiedQueue[Element]) Peek() (Element, error) {
+	item, _, err := q.PeekID()
+	return item, err
+}
+
+// PeekID returns the first element of the queue along with the ID assigned when it was pushed.
+// If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *IdentifiedQueue[Element]) PeekID() (Element, string, error) {
+	entry, err := q.items.Peek()
+	return entry.item, entry.id, err
+}
+
+// Length returns the number of elements in the queue.
+func (q *IdentifiedQueue[Element]) Length() int {
+	return q.items.Length()
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them.
+func (q *IdentifiedQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		for entry := range q.items.All() {
+			if !yield(entry.item) {
+				return
+			}
+		}
+	}
+}
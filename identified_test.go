@@ -0,0 +1,53 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifiedQueue(t *testing.T) {
+	t.Run("cannot pop or peek empty queue", func(t *testing.T) {
+		q := NewIdentifiedQueue[string](1)
+
+		_, _, err := q.PopID()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, _, err = q.PeekID()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("ids increase with each push", func(t *testing.T) {
+		q := NewIdentifiedQueue[string](2)
+
+		first := q.PushID("a")
+		second := q.PushID("b")
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", first)
+		assert.Less(t, first, second)
+	})
+
+	t.Run("front id is stable across resize and pops", func(t *testing.T) {
+		q := NewIdentifiedQueue[string](2)
+		q.PushID("a")
+		secondID := q.PushID("b")
+
+		x, frontID, err := q.PeekID()
+		assert.NoError(t, err)
+		assert.Equal(t, "a", x)
+
+		for _, item := range []string{"c", "d", "e"} {
+			assert.NoError(t, q.Push(item))
+		}
+
+		x, id, err := q.PopID()
+		assert.NoError(t, err)
+		assert.Equal(t, "a", x)
+		assert.Equal(t, frontID, id)
+
+		x, id, err = q.PeekID()
+		assert.NoError(t, err)
+		assert.Equal(t, "b", x)
+		assert.Equal(t, secondID, id)
+		assert.Equal(t, 4, q.Length())
+	})
+}
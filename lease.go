@@ -0,0 +1,85 @@
+package queue
+
+// LeaseToken identifies an element leased from a LeaseQueue.
+type LeaseToken uint64
+
+// LeaseQueue is an unbounded queue for at-least-once processing. Leasing an element hides it from later leases
+// until it is acknowledged, which removes it for good, or negatively acknowledged, which returns it to the queue ahead
+// of every element pushed after it, so that redelivered elements keep the order they were pushed in.
+type LeaseQueue[Element any] struct {
+	items  *RingBuffer[sequencedElement[Element]]
+	leases map[LeaseToken]sequencedElement[Element]
+	next   LeaseToken
+	pushed uint64
+}
+
+// NewLeaseQueue returns a new lease queue with the specific initial capacity.
+func NewLeaseQueue[Element any](initialCapacity int) *LeaseQueue[Element] {
+	return &LeaseQueue[Element]{
+		items:  NewUnboundedRingBuffer[sequencedElement[Element]](initialCapacity),
+		leases: make(map[LeaseToken]sequencedElement[Element]),
+	}
+}
+
+// Push adds an element to the end of the queue. It always returns nil.
+func (q *LeaseQueue[Element]) Push(item Element) error {
+	q.pushed++
+	return q.items.Push(sequencedElement[Element]{item: item, seq: q.pushed})
+}
+
+// Lease returns the first unleased element of the queue, along with a token identifying the lease, and hides the
+// element from later leases. If no unleased element remains, the ErrQueueEmpty error is returned.
+func (q *LeaseQueue[Element]) Lease() (Element, LeaseToken, error) {
+	entry, err := q.items.Pop()
+	if err != nil {
+		return entry.item, 0, err
+	}
+
+	q.next++
+	q.leases[q.next] = entry
+
+	return entry.item, q.next, nil
+}
+
+// Ack removes a leased element from the queue for good.
+// If token does not identify an outstanding lease, the ErrUnknownLease error is returned.
+func (q *LeaseQueue[Element]) Ack(token LeaseToken) error {
+	if _, ok := q.leases[token]; !ok {
+		return ErrUnknownLease
+	}
+
+	delete(q.leases, token)
+
+	return nil
+}
+
+// Nack returns a leased element to the queue, ahead of every element pushed after it, so that when several leases are
+// nacked their elements are leased again in the order they were pushed.
+// If token does not identify an outstanding lease, the ErrUnknownLease error is returned.
+func (q *LeaseQueue[Element]) Nack(token LeaseToken) error {
+	entry, ok := q.leases[token]
+	if !ok {
+		return ErrUnknownLease
+	}
+
+	delete(q.leases, token)
+
+	// nacked elements are few and near the front, so the element is moved back from the front to its place.
+	q.items.pushFront(entry)
+	for i := 1; i < q.items.length && q.items.at(i).seq < entry.seq; i++ {
+		q.items.set(i-1, q.items.at(i))
+		q.items.set(i, entry)
+	}
+
+	return nil
+}
+
+// Length returns the number of unleased elements in the queue.
+func (q *LeaseQueue[Element]) Length() int {
+	return q.items.Length()
+}
+
+// Leased returns the number of outstanding leases.
+func (q *LeaseQueue[Element]) Leased() int {
+	return len(q.leases)
+}
@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaseQueue(t *testing.T) {
+	t.Run("cannot lease from empty queue", func(t *testing.T) {
+		q := NewLeaseQueue[int](1)
+
+		_, _, err := q.Lease()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("ack removes leased element", func(t *testing.T) {
+		q := NewLeaseQueue[int](2)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		x, token, err := q.Lease()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+		assert.Equal(t, 1, q.Length())
+		assert.Equal(t, 1, q.Leased())
+
+		assert.NoError(t, q.Ack(token))
+		assert.Equal(t, 0, q.Leased())
+		assert.ErrorIs(t, q.Ack(token), ErrUnknownLease)
+
+		x, _, err = q.Lease()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+	})
+
+	t.Run("leased element is hidden until nacked", func(t *testing.T) {
+		q := NewLeaseQueue[int](2)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		_, first, err := q.Lease()
+		assert.NoError(t, err)
+
+		x, second, err := q.Lease()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+
+		_, _, err = q.Lease()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		assert.NoError(t, q.Nack(first))
+		assert.ErrorIs(t, q.Nack(first), ErrUnknownLease)
+		assert.Equal(t, 1, q.Length())
+
+		x, _, err = q.Lease()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+
+		assert.NoError(t, q.Push(3))
+		assert.NoError(t, q.Nack(second))
+
+		x, _, err = q.Lease()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+	})
+
+	t.Run("nacked elements are redelivered in push order", func(t *testing.T) {
+		q := NewLeaseQueue[int](3)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+		assert.NoError(t, q.Push(3))
+
+		_, first, err := q.Lease()
+		assert.NoError(t, err)
+		_, second, err := q.Lease()
+		assert.NoError(t, err)
+
+		assert.NoError(t, q.Nack(first))
+		assert.NoError(t, q.Nack(second))
+
+		for _, want := range []int{1, 2, 3} {
+			x, _, err := q.Lease()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x)
+		}
+	})
+}
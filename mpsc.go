@@ -0,0 +1,95 @@
+package queue
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// MPSCQueue is a bounded ring buffer-based queue that is safe for use by multiple producers and a single consumer.
+// Pushes from concurrent producers are serialized by a mutex, while the consumer reads without locking.
+// Elements pushed by one producer are popped in the order that producer pushed them.
+type MPSCQueue[Element any] struct {
+	items    []Element
+	head     atomic.Uint64
+	tail     atomic.Uint64
+	pushLock sync.Mutex
+}
+
+var _ Queue[int] = (*MPSCQueue[int])(nil)
+
+// NewMPSCQueue returns a new multi-producer, single-consumer queue with a maximum specific capacity.
+func NewMPSCQueue[Element any](capacity int) *MPSCQueue[Element] {
+	if capacity == 0 {
+		capacity = defaultRingBufferQueueCapacity
+	}
+
+	return &MPSCQueue[Element]{
+		items: make([]Element, capacity),
+	}
+}
+
+// Push adds an element to the end of the queue. If the queue cannot accept more elements, the ErrQueueFull error is returned.
+// Push may be called by any number of goroutines concurrently.
+func (q *MPSCQueue[Element]) Push(item Element) error {
+	q.pushLock.Lock()
+	defer q.pushLock.Unlock()
+
+	tail := q.tail.Load()
+	if tail-q.head.Load() == uint64(cap(q.items)) {
+		return ErrQueueFull
+	}
+
+	q.items[tail%uint64(cap(q.items))] = item
+	q.tail.Store(tail + 1)
+
+	return nil
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+// Pop must only be called by the single consumer goroutine.
+func (q *MPSCQueue[Element]) Pop() (Element, error) {
+	item, err := q.Peek()
+	if err != nil {
+		return item, err
+	}
+
+	q.head.Add(1)
+
+	return item, nil
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+// Peek must only be called by the single consumer goroutine.
+func (q *MPSCQueue[Element]) Peek() (Element, error) {
+	var item Element
+
+	head := q.head.Load()
+	if head == q.tail.Load() {
+		return item, ErrQueueEmpty
+	}
+
+	return q.items[head%uint64(cap(q.items))], nil
+}
+
+// Length returns the number of elements in the queue. It may be called from any goroutine.
+func (q *MPSCQueue[Element]) Length() int {
+	// loading head first guarantees that the tail loaded afterwards is not behind it.
+	head := q.head.Load()
+	return int(q.tail.Load() - head)
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them. It yields the
+// elements pushed before iteration begins, as elements pushed during iteration may not be visible yet.
+// All must only be called by the single consumer goroutine, which must not pop while iterating.
+func (q *MPSCQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		head, tail := q.head.Load(), q.tail.Load()
+
+		for i := head; i < tail; i++ {
+			if !yield(q.items[i%uint64(cap(q.items))]) {
+				return
+			}
+		}
+	}
+}
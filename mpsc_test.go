@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMPSCQueue(t *testing.T) {
+	t.Run("push and pop in order", func(t *testing.T) {
+		q := NewMPSCQueue[int](2)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+		assert.Equal(t, 2, q.Length())
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+
+		x, err = q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+
+		x, err = q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+		assert.Equal(t, 0, q.Length())
+	})
+
+	t.Run("cannot pop or peek empty queue", func(t *testing.T) {
+		q := NewMPSCQueue[int](1)
+
+		_, err := q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, err = q.Peek()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("all yields elements from front to back", func(t *testing.T) {
+		q := NewMPSCQueue[int](3)
+		for _, x := range []int{1, 2, 3} {
+			assert.NoError(t, q.Push(x))
+		}
+		_, _ = q.Pop()
+		assert.NoError(t, q.Push(4))
+
+		assert.Equal(t, []int{2, 3, 4}, slices.Collect(q.All()))
+		assert.Equal(t, 3, q.Length())
+	})
+
+	t.Run("cannot push to full queue", func(t *testing.T) {
+		q := NewMPSCQueue[int](1)
+		assert.NoError(t, q.Push(1))
+		assert.ErrorIs(t, q.Push(2), ErrQueueFull)
+	})
+
+	t.Run("concurrent producers with single consumer", func(t *testing.T) {
+		const producerCount = 4
+		const itemsPerProducer = 1000
+
+		q := NewMPSCQueue[int](16)
+
+		var wg sync.WaitGroup
+		for p := range producerCount {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range itemsPerProducer {
+					for q.Push(p*itemsPerProducer+i) != nil {
+						runtime.Gosched()
+					}
+				}
+			}()
+		}
+
+		next := make([]int, producerCount)
+		for received := 0; received < producerCount*itemsPerProducer; {
+			x, err := q.Pop()
+			if err != nil {
+				runtime.Gosched()
+				continue
+			}
+
+			producer, seq := x/itemsPerProducer, x%itemsPerProducer
+			assert.Equal(t, next[producer], seq)
+			next[producer] = seq + 1
+			received++
+		}
+
+		wg.Wait()
+		assert.Equal(t, 0, q.Length())
+		for _, n := range next {
+			assert.Equal(t, itemsPerProducer, n)
+		}
+	})
+}
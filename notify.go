@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"iter"
+	"sync"
+)
+
+// NotifyingQueue is a bounded queue, safe for concurrent use, that offers channels signalling when it has elements to
+// pop and when it has room to push, so that consumers and producers can wait in a select statement instead of polling.
+type NotifyingQueue[Element any] struct {
+	mu       sync.Mutex
+	q        *RingBuffer[Element]
+	notEmpty chan struct{}
+	notFull  chan struct{}
+}
+
+var _ Queue[int] = (*NotifyingQueue[int])(nil)
+
+// NewNotifyingQueue returns a new notifying queue that holds at most capacity elements.
+func NewNotifyingQueue[Element any](capacity int, opts ...Option) *NotifyingQueue[Element] {
+	q := NewBoundedRingBuffer[Element](capacity, opts...)
+
+	w := &NotifyingQueue[Element]{
+		q:        q,
+		notEmpty: make(chan struct{}),
+		notFull:  make(chan struct{}),
+	}
+
+	if q.Cap() > 0 {
+		close(w.notFull)
+	}
+
+	return w
+}
+
+// Push adds an element to the end of the queue. If the queue cannot accept more elements, the ErrQueueFull error is returned.
+func (w *NotifyingQueue[Element]) Push(item Element) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.q.Push(item); err != nil {
+		return err
+	}
+
+	if w.q.Length() == 1 {
+		close(w.notEmpty)
+	}
+
+	if w.q.Length() == w.q.Cap() {
+		w.notFull = make(chan struct{})
+	}
+
+	return nil
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (w *NotifyingQueue[Element]) Pop() (Element, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	item, err := w.q.Pop()
+	if err != nil {
+		return item, err
+	}
+
+	if w.q.Length() == 0 {
+		w.notEmpty = make(chan struct{})
+	}
+
+	if w.q.Length() == w.q.Cap()-1 {
+		close(w.notFull)
+	}
+
+	return item, nil
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (w *NotifyingQueue[Element]) Peek() (Element, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.q.Peek()
+}
+
+// Length returns the number of elements in the queue.
+func (w *NotifyingQueue[Element]) Length() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.q.Length()
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them.
+// The iterator yields a snapshot of the elements taken while holding the mutex when iteration begins.
+func (w *NotifyingQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		w.mu.Lock()
+		items := w.q.toSlice()
+		w.mu.Unlock()
+
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// NotEmpty returns a channel that is closed once the queue holds at least one element. The channel is already closed
+// if the queue is not empty. Once the queue becomes empty again, later calls return a new channel, so NotEmpty must be
+// called again before each wait. As other consumers may pop first, a Pop after the channel is closed can still fail.
+func (w *NotifyingQueue[Element]) NotEmpty() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.notEmpty
+}
+
+// NotFull returns a channel that is closed once the queue has room for at least one more element, behaving like the
+// channel returned by NotEmpty.
+func (w *NotifyingQueue[Element]) NotFull() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.notFull
+}
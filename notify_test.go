@@ -0,0 +1,79 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestNotifyingQueue(t *testing.T) {
+	t.Run("waiting consumer is released by push", func(t *testing.T) {
+		q := NewNotifyingQueue[int](2)
+
+		popped := make(chan int)
+		go func() {
+			<-q.NotEmpty()
+			x, _ := q.Pop()
+			popped <- x
+		}()
+
+		assert.NoError(t, q.Push(7))
+
+		select {
+		case x := <-popped:
+			assert.Equal(t, 7, x)
+		case <-time.After(time.Second):
+			t.Fatal("consumer was not released")
+		}
+	})
+
+	t.Run("not empty channel is refreshed", func(t *testing.T) {
+		q := NewNotifyingQueue[int](2)
+		assert.False(t, isClosed(q.NotEmpty()))
+
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+		assert.True(t, isClosed(q.NotEmpty()))
+
+		_, _ = q.Pop()
+		assert.True(t, isClosed(q.NotEmpty()))
+
+		_, _ = q.Pop()
+		waiting := q.NotEmpty()
+		assert.False(t, isClosed(waiting))
+
+		assert.NoError(t, q.Push(3))
+		assert.True(t, isClosed(waiting))
+	})
+
+	t.Run("not full channel is refreshed", func(t *testing.T) {
+		q := NewNotifyingQueue[int](2)
+		assert.True(t, isClosed(q.NotFull()))
+
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+		waiting := q.NotFull()
+		assert.False(t, isClosed(waiting))
+		assert.ErrorIs(t, q.Push(3), ErrQueueFull)
+
+		x, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+		assert.True(t, isClosed(waiting))
+		assert.Equal(t, 1, q.Length())
+
+		x, err = q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+	})
+}
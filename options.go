@@ -0,0 +1,70 @@
+package queue
+
+import "time"
+
+// Option configures optional behavior of a queue.
+type Option func(*options)
+
+type options struct {
+	compactThreshold float64
+	clock            Clock
+	latencyRecorder  LatencyRecorder
+	lazyAllocation   bool
+}
+
+// LatencyRecorder receives the measured duration of queue operations.
+type LatencyRecorder interface {
+	// RecordPush is called with the duration of each successful push.
+	RecordPush(time.Duration)
+
+	// RecordPop is called with the duration of each successful pop.
+	RecordPop(time.Duration)
+}
+
+// WithCompactThreshold makes the queue compact its internal storage after a pop, whenever the unused space ahead of
+// the first element exceeds the given fraction of the queue's capacity. Compacting moves the elements to the start of
+// the internal storage without changing its capacity, which avoids copying wrapped elements separately when an
+// unbounded queue next resizes. A fraction of zero or less disables compaction, which is the default.
+func WithCompactThreshold(frac float64) Option {
+	return func(o *options) {
+		o.compactThreshold = frac
+	}
+}
+
+// WithClock makes a queue whose behavior depends on time read the current time from clock, rather than from the
+// system clock.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithLatencyRecorder makes the queue measure the duration of each successful push and pop, and report it to rec.
+// Durations are measured with the queue's clock. Without a recorder, operations are not timed.
+func WithLatencyRecorder(rec LatencyRecorder) Option {
+	return func(o *options) {
+		o.latencyRecorder = rec
+	}
+}
+
+// WithLazyAllocation makes an unbounded queue start with small internal storage, rather than allocating its whole
+// initial capacity up front, and grow as elements are pushed. This avoids allocating and zeroing storage for a large
+// initial capacity that may never be used, at the cost of resizing and copying elements while the queue fills.
+// It has no effect on bounded queues.
+func WithLazyAllocation() Option {
+	return func(o *options) {
+		o.lazyAllocation = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		clock: systemClock{},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
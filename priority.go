@@ -0,0 +1,195 @@
+package queue
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"time"
+)
+
+// BoundedPriorityQueue is a queue with a maximum capacity that pops elements in priority order, rather than in the
+// order they were pushed. Elements of equal priority are popped in the order they were pushed.
+// When the queue is full, pushing an element of higher priority than the lowest priority element evicts that element.
+type BoundedPriorityQueue[Element any] struct {
+	// items is kept sorted from lowest to highest priority.
+	items   []prioritizedElement[Element]
+	less    func(a, b Element) bool
+	next    uint64
+	now     time.Time
+	options priorityOptions[Element]
+}
+
+var _ Queue[int] = (*BoundedPriorityQueue[int])(nil)
+
+type prioritizedElement[Element any] struct {
+	item     Element
+	pushedAt time.Time
+	seq      uint64
+}
+
+// PriorityOption configures optional behavior of a BoundedPriorityQueue.
+type PriorityOption[Element any] func(*priorityOptions[Element])
+
+type priorityOptions[Element any] struct {
+	priority func(Element) int
+	aging    func(age time.Duration) int
+	clock    Clock
+}
+
+// WithAging makes the queue order elements by priority(e) + rate(age), highest first, where age is how long the
+// element has waited since it was pushed, as measured with the queue's clock. The queue's less function then only
+// breaks ties between elements of equal aged priority, ahead of push order. The order is brought up to date on each
+// push, pop and peek, so that low priority elements are not starved by a steady stream of high priority ones. The
+// rate function should not decrease as age grows.
+func WithAging[Element any](priority func(Element) int, rate func(age time.Duration) int) PriorityOption[Element] {
+	return func(o *priorityOptions[Element]) {
+		o.priority = priority
+		o.aging = rate
+	}
+}
+
+// WithPriorityClock makes the queue measure the age of elements with clock, rather than with the system clock.
+func WithPriorityClock[Element any](clock Clock) PriorityOption[Element] {
+	return func(o *priorityOptions[Element]) {
+		o.clock = clock
+	}
+}
+
+// NewBoundedPriorityQueue returns a new priority queue with a maximum specific capacity.
+// The less function reports whether a has a lower priority than b.
+func NewBoundedPriorityQueue[Element any](capacity int, less func(a, b Element) bool, opts ...PriorityOption[Element]) *BoundedPriorityQueue[Element] {
+	if capacity == 0 {
+		capacity = defaultRingBufferQueueCapacity
+	}
+
+	q := &BoundedPriorityQueue[Element]{
+		items: make([]prioritizedElement[Element], 0, capacity),
+		less:  less,
+		options: priorityOptions[Element]{
+			clock: systemClock{},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&q.options)
+	}
+
+	return q
+}
+
+// Push adds an element to the queue. If the queue is full and the element has a higher priority than the lowest
+// priority element, the lowest priority element is evicted to make room. Otherwise, if the queue is full, the
+// ErrQueueFull error is returned.
+func (q *BoundedPriorityQueue[Element]) Push(item Element) error {
+	q.reorder()
+
+	q.next++
+	entry := prioritizedElement[Element]{item: item, pushedAt: q.now, seq: q.next}
+
+	if len(q.items) == cap(q.items) {
+		if q.compare(q.items[0], entry) >= 0 {
+			return ErrQueueFull
+		}
+
+		q.items = slices.Delete(q.items, 0, 1)
+	}
+
+	// inserting ahead of elements of equal priority lets earlier pushes pop first.
+	index, _ := slices.BinarySearchFunc(q.items, entry, func(e, target prioritizedElement[Element]) int {
+		if q.compare(e, target) < 0 {
+			return -1
+		}
+
+		return 1
+	})
+
+	q.items = slices.Insert(q.items, index, entry)
+
+	return nil
+}
+
+// Pop removes and returns the highest priority element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *BoundedPriorityQueue[Element]) Pop() (Element, error) {
+	item, err := q.Peek()
+	if err != nil {
+		return item, err
+	}
+
+	q.items = slices.Delete(q.items, len(q.items)-1, len(q.items))
+
+	return item, nil
+}
+
+// Peek returns the highest priority element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *BoundedPriorityQueue[Element]) Peek() (Element, error) {
+	var item Element
+
+	if len(q.items) == 0 {
+		return item, ErrQueueEmpty
+	}
+
+	q.reorder()
+
+	return q.items[len(q.items)-1].item, nil
+}
+
+// Length returns the number of elements in the queue.
+func (q *BoundedPriorityQueue[Element]) Length() int {
+	return len(q.items)
+}
+
+// All returns an iterator over the elements of the queue, from highest to lowest priority, without removing them.
+// The iterator yields a snapshot of the elements taken when iteration begins.
+func (q *BoundedPriorityQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		q.reorder()
+
+		for _, entry := range slices.Backward(slices.Clone(q.items)) {
+			if !yield(entry.item) {
+				return
+			}
+		}
+	}
+}
+
+// compare orders a before b if it has a lower priority. With aging, elements of equal effective priority are ordered
+// by the less function and then so that the earlier push has the higher priority; without it, the order of elements of
+// equal priority is kept by where they were inserted.
+func (q *BoundedPriorityQueue[Element]) compare(a, b prioritizedElement[Element]) int {
+	if q.options.aging == nil {
+		if q.less(a.item, b.item) {
+			return -1
+		}
+
+		return 0
+	}
+
+	if c := cmp.Compare(q.effectivePriority(a), q.effectivePriority(b)); c != 0 {
+		return c
+	}
+
+	if q.less(a.item, b.item) {
+		return -1
+	}
+
+	if q.less(b.item, a.item) {
+		return 1
+	}
+
+	return cmp.Compare(b.seq, a.seq)
+}
+
+func (q *BoundedPriorityQueue[Element]) effectivePriority(e prioritizedElement[Element]) int {
+	return q.options.priority(e.item) + q.options.aging(q.now.Sub(e.pushedAt))
+}
+
+// reorder brings the order of the elements up to date with the current time, as aging changes their relative
+// priorities.
+func (q *BoundedPriorityQueue[Element]) reorder() {
+	if q.options.aging == nil {
+		return
+	}
+
+	q.now = q.options.clock.Now()
+	slices.SortFunc(q.items, q.compare)
+}
@@ -0,0 +1,138 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundedPriorityQueue(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	t.Run("cannot pop from empty queue", func(t *testing.T) {
+		q := NewBoundedPriorityQueue(2, less)
+
+		_, err := q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, err = q.Peek()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("pops in priority order", func(t *testing.T) {
+		q := NewBoundedPriorityQueue(5, less)
+		for _, x := range []int{3, 1, 4, 1, 5} {
+			assert.NoError(t, q.Push(x))
+		}
+
+		for _, want := range []int{5, 4, 3, 1, 1} {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x)
+		}
+	})
+
+	t.Run("equal priorities pop in push order", func(t *testing.T) {
+		type job struct {
+			priority int
+			name     string
+		}
+
+		q := NewBoundedPriorityQueue(3, func(a, b job) bool { return a.priority < b.priority })
+		assert.NoError(t, q.Push(job{1, "a"}))
+		assert.NoError(t, q.Push(job{1, "b"}))
+		assert.NoError(t, q.Push(job{1, "c"}))
+
+		for _, want := range []string{"a", "b", "c"} {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x.name)
+		}
+	})
+
+	t.Run("full queue evicts lowest for higher priority", func(t *testing.T) {
+		q := NewBoundedPriorityQueue(3, less)
+		assert.NoError(t, q.Push(2))
+		assert.NoError(t, q.Push(5))
+		assert.NoError(t, q.Push(3))
+
+		assert.NoError(t, q.Push(4))
+		assert.Equal(t, 3, q.Length())
+
+		assert.ErrorIs(t, q.Push(1), ErrQueueFull)
+		assert.ErrorIs(t, q.Push(3), ErrQueueFull)
+
+		for _, want := range []int{5, 4, 3} {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x)
+		}
+	})
+}
+
+func TestBoundedPriorityQueueAging(t *testing.T) {
+	type job struct {
+		priority int
+		name     string
+	}
+
+	less := func(a, b job) bool { return a.priority < b.priority }
+	priority := func(j job) int { return j.priority }
+	rate := func(age time.Duration) int { return int(age / time.Second) }
+
+	t.Run("old low priority element overtakes newer high priority elements", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewBoundedPriorityQueue(3, less, WithAging(priority, rate), WithPriorityClock[job](clock))
+
+		assert.NoError(t, q.Push(job{0, "old"}))
+		clock.Advance(3 * time.Second)
+		assert.NoError(t, q.Push(job{5, "high"}))
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, "high", x.name)
+
+		clock.Advance(7 * time.Second)
+		assert.NoError(t, q.Push(job{5, "newer high"}))
+
+		for _, want := range []string{"high", "old", "newer high"} {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x.name)
+		}
+	})
+
+	t.Run("full queue evicts lowest effective priority", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewBoundedPriorityQueue(2, less, WithAging(priority, rate), WithPriorityClock[job](clock))
+
+		assert.NoError(t, q.Push(job{0, "old"}))
+		clock.Advance(10 * time.Second)
+		assert.NoError(t, q.Push(job{3, "new"}))
+
+		assert.NoError(t, q.Push(job{4, "newest"}))
+
+		var names []string
+		for x := range q.All() {
+			names = append(names, x.name)
+		}
+		assert.Equal(t, []string{"old", "newest"}, names)
+	})
+
+	t.Run("less breaks ties between equal aged priorities", func(t *testing.T) {
+		clock := newFakeClock()
+		byName := func(a, b job) bool { return a.name < b.name }
+		q := NewBoundedPriorityQueue(3, byName, WithAging(priority, rate), WithPriorityClock[job](clock))
+
+		assert.NoError(t, q.Push(job{1, "a"}))
+		assert.NoError(t, q.Push(job{1, "c"}))
+		assert.NoError(t, q.Push(job{1, "b"}))
+
+		for _, want := range []string{"c", "b", "a"} {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x.name)
+		}
+	})
+}
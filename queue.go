@@ -1,7 +1,21 @@
 // Package queue provides a ring buffer-based queue implementation.
 package queue
 
-import "errors"
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"iter"
+	"maps"
+	"math/bits"
+	"slices"
+	"time"
+	"unsafe"
+)
 
 var (
 	// ErrQueueEmpty is an error returned when an attempt is made to take an element from an empty queue.
@@ -9,6 +23,36 @@ var (
 
 	// ErrQueueFull is an error returned when an attempt is made to add an element to a full queue.
 	ErrQueueFull = errors.New("queue is full and cannot accept more elements")
+
+	// ErrCapacityTooSmall is an error returned when an attempt is made to set a queue's capacity below its length.
+	ErrCapacityTooSmall = errors.New("capacity is too small to hold the elements of the queue")
+
+	// ErrFixedStorage is an error returned when an attempt is made to reallocate storage supplied by the caller.
+	ErrFixedStorage = errors.New("queue storage is supplied by the caller and cannot be reallocated")
+
+	// ErrIndexOutOfRange is an error returned when an index outside of the queue's elements is given.
+	ErrIndexOutOfRange = errors.New("index is out of range of the queue")
+
+	// ErrNoneReady is an error returned when no element of a DelayQueue has reached its release time.
+	ErrNoneReady = errors.New("no element is ready for release")
+
+	// ErrRateLimited is an error returned when an element of a RateLimitedQueue is popped before its interval has passed.
+	ErrRateLimited = errors.New("pop is rate limited")
+
+	// ErrUnknownReservation is an error returned when a reservation token is not outstanding.
+	ErrUnknownReservation = errors.New("reservation token is not outstanding")
+
+	// ErrUnknownLease is an error returned when a lease token is not outstanding.
+	ErrUnknownLease = errors.New("lease token is not outstanding")
+
+	// ErrInvalidClass is an error returned when an element is assigned a class that a ClassQueue does not have.
+	ErrInvalidClass = errors.New("element class is out of range")
+
+	// ErrInvalidPercentile is an error returned when a percentile that is not a number is requested.
+	ErrInvalidPercentile = errors.New("percentile is not a number")
+
+	// ErrInvalidQueue is an error returned by Validate when the internal state of a queue is inconsistent.
+	ErrInvalidQueue = errors.New("queue internal state is invalid")
 )
 
 type Queue[Element any] interface {
@@ -23,75 +67,301 @@ type Queue[Element any] interface {
 
 	// Length returns the number of elements in the queue.
 	Length() int
+
+	// All returns an iterator over the elements of the queue, in the order they would be popped, without removing them.
+	All() iter.Seq[Element]
+}
+
+// ReadOnlyQueue is a view of a queue's elements that offers no way to modify them.
+type ReadOnlyQueue[Element any] interface {
+	// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+	Peek() (Element, error)
+
+	// At returns the element at index i of the queue, where index zero is the front.
+	// If i is outside of [0, Length()), the ErrIndexOutOfRange error is returned.
+	At(i int) (Element, error)
+
+	// Length returns the number of elements in the queue.
+	Length() int
+
+	// All returns an iterator over the elements of the queue, from front to back.
+	All() iter.Seq[Element]
 }
 
-type ringBufferQueue[Element any] struct {
-	items   []Element
-	front   int
-	length  int
-	bounded bool
+// RingBuffer is the ring buffer-based implementation of Queue returned by NewBoundedQueue and NewUnboundedQueue.
+// Using it directly, rather than through the Queue interface, avoids the cost of dynamic dispatch in hot loops.
+// The methods implementing Queue behave as documented on the Queue interface.
+type RingBuffer[Element any] struct {
+	items    []Element
+	front    int
+	length   int
+	bounded  bool
+	fixed    bool
+	growth   GrowthPolicy
+	overflow OverflowPolicy
+	rejected int
+	options  options
 }
 
+// OverflowPolicy determines what a bounded queue does when an element is pushed while it is full.
+type OverflowPolicy int
+
+const (
+	// Reject makes Push return the ErrQueueFull error, leaving the queue unchanged.
+	Reject OverflowPolicy = iota
+
+	// DropOldest makes Push remove the first element of the queue to make room for the new element.
+	DropOldest
+
+	// DropNewest makes Push discard the new element, leaving the queue unchanged, and return nil.
+	DropNewest
+)
+
 // NewBoundedQueue returns a new queue with a maximum specific capacity.
-func NewBoundedQueue[Element any](capacity int) Queue[Element] {
-	return newBoundedRingBufferQueue[Element](capacity)
+func NewBoundedQueue[Element any](capacity int, opts ...Option) Queue[Element] {
+	return NewBoundedRingBuffer[Element](capacity, opts...)
+}
+
+// NewBoundedQueuePolicy returns a new ring buffer with a maximum specific capacity, which handles pushes while it is
+// full according to the given overflow policy.
+func NewBoundedQueuePolicy[Element any](capacity int, policy OverflowPolicy, opts ...Option) *RingBuffer[Element] {
+	q := NewBoundedRingBuffer[Element](capacity, opts...)
+	q.overflow = policy
+
+	return q
+}
+
+// NewStaticQueue returns a new bounded queue that stores its elements in backing, with a maximum capacity of
+// len(backing). The queue never allocates storage of its own, and it overwrites the existing contents of backing.
+func NewStaticQueue[Element any](backing []Element, opts ...Option) *RingBuffer[Element] {
+	return &RingBuffer[Element]{
+		items:   backing[:len(backing):len(backing)],
+		bounded: true,
+		fixed:   true,
+		options: newOptions(opts),
+	}
 }
 
 // NewUnboundedQueue returns a new queue with the specific initial capacity.
 // The queue will resize its internal storage if its current capacity is exceeded.
 // This implementation will double the internal capacity during each resize operation.
-func NewUnboundedQueue[Element any](initialCapacity int) Queue[Element] {
-	return newUnboundedRingBufferQueue[Element](initialCapacity)
+func NewUnboundedQueue[Element any](initialCapacity int, opts ...Option) Queue[Element] {
+	return NewUnboundedRingBuffer[Element](initialCapacity, opts...)
+}
+
+// NewUnboundedQueueFrom returns a new unbounded queue holding the elements of src in the same order.
+// The elements are read by iterating over src, which is left unchanged.
+func NewUnboundedQueueFrom[Element any](src Queue[Element], opts ...Option) *RingBuffer[Element] {
+	q := NewUnboundedRingBuffer[Element](src.Length(), opts...)
+	for item := range src.All() {
+		_ = q.Push(item)
+	}
+
+	return q
+}
+
+// CollectSeq returns a new unbounded queue holding the values yielded by seq, in the order they were yielded.
+func CollectSeq[Element any](seq iter.Seq[Element], opts ...Option) *RingBuffer[Element] {
+	q := NewUnboundedRingBuffer[Element](0, opts...)
+	for item := range seq {
+		_ = q.Push(item)
+	}
+
+	return q
+}
+
+// FromMapSorted returns a new unbounded queue holding the values of m, in ascending order of their keys.
+func FromMapSorted[K cmp.Ordered, V any](m map[K]V, opts ...Option) *RingBuffer[V] {
+	q := NewUnboundedRingBuffer[V](len(m), opts...)
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		_ = q.Push(m[key])
+	}
+
+	return q
 }
 
-const defaultRingBufferQueueCapacity = 2
+// NewUnboundedQueueReserve returns a new unbounded queue that can hold at least expectedMax elements before it must
+// resize its internal storage. The initial capacity is expectedMax rounded up to a power of two.
+func NewUnboundedQueueReserve[Element any](expectedMax int, opts ...Option) *RingBuffer[Element] {
+	capacity := defaultRingBufferQueueCapacity
+	if expectedMax > 0 {
+		capacity = 1 << bits.Len(uint(expectedMax-1))
+	}
+
+	return NewUnboundedRingBuffer[Element](capacity, opts...)
+}
+
+// NewUnboundedQueueForThroughput returns a new unbounded queue sized for an expected load, where elements are pushed at
+// pushesPerSec and popped at popsPerSec. Its initial capacity is the backlog that builds up over window when pushes
+// outpace pops, (pushesPerSec - popsPerSec) * window in seconds, and at least one. A queue whose load stays within
+// that profile for no longer than window does not need to resize its internal storage.
+func NewUnboundedQueueForThroughput[Element any](pushesPerSec, popsPerSec int, window time.Duration, opts ...Option) *RingBuffer[Element] {
+	capacity := max(1, int(float64(pushesPerSec-popsPerSec)*window.Seconds()))
+
+	return NewUnboundedRingBuffer[Element](capacity, opts...)
+}
+
+var _ Queue[int] = (*RingBuffer[int])(nil)
+
+const (
+	defaultRingBufferQueueCapacity = 2
 
-func newBoundedRingBufferQueue[Element any](capacity int) Queue[Element] {
+	// lazyRingBufferQueueCapacity is the largest initial capacity allocated when lazy allocation is enabled.
+	lazyRingBufferQueueCapacity = 16
+)
+
+// NewBoundedRingBuffer returns a new ring buffer with a maximum specific capacity.
+// It is equivalent to NewBoundedQueue, but returns the concrete type.
+func NewBoundedRingBuffer[Element any](capacity int, opts ...Option) *RingBuffer[Element] {
 	if capacity == 0 {
 		capacity = defaultRingBufferQueueCapacity
 	}
 
-	return &ringBufferQueue[Element]{
+	return &RingBuffer[Element]{
 		items:   make([]Element, capacity),
 		bounded: true,
+		options: newOptions(opts),
 	}
 }
 
-func newUnboundedRingBufferQueue[Element any](initialCapacity int) Queue[Element] {
+// NewUnboundedRingBuffer returns a new ring buffer with the specific initial capacity, which resizes as needed.
+// It is equivalent to NewUnboundedQueue, but returns the concrete type.
+func NewUnboundedRingBuffer[Element any](initialCapacity int, opts ...Option) *RingBuffer[Element] {
 	if initialCapacity == 0 {
 		initialCapacity = defaultRingBufferQueueCapacity
 	}
 
-	return &ringBufferQueue[Element]{
+	options := newOptions(opts)
+	if options.lazyAllocation {
+		initialCapacity = min(initialCapacity, lazyRingBufferQueueCapacity)
+	}
+
+	return &RingBuffer[Element]{
 		items:   make([]Element, initialCapacity),
 		bounded: false,
+		options: options,
+	}
+}
+
+func (q *RingBuffer[Element]) at(index int) Element {
+	return q.items[(q.front+index)%cap(q.items)]
+}
+
+func (q *RingBuffer[Element]) set(index int, item Element) {
+	q.items[(q.front+index)%cap(q.items)] = item
+}
+
+// removeAt removes the element at the given index, moving the elements behind it forward.
+func (q *RingBuffer[Element]) removeAt(index int) {
+	for i := index; i < q.length-1; i++ {
+		q.set(i, q.at(i+1))
+	}
+
+	q.length--
+}
+
+// toSlice returns a new slice holding the elements in order.
+func (q *RingBuffer[Element]) toSlice() []Element {
+	items := make([]Element, q.length)
+	q.PeekInto(items)
+
+	return items
+}
+
+func (q *RingBuffer[Element]) isWrapped() bool {
+	return q.front+q.length > cap(q.items)
+}
+
+// discardFront removes count elements from the front, compacting afterwards if the compact threshold is exceeded.
+func (q *RingBuffer[Element]) discardFront(count int) {
+	if count == 0 {
+		return
+	}
+
+	q.front = (q.front + count) % cap(q.items)
+	q.length -= count
+
+	if threshold := q.options.compactThreshold; threshold > 0 && float64(q.front) > threshold*float64(cap(q.items)) {
+		q.compact()
 	}
 }
 
-func (q *ringBufferQueue[Element]) expand() {
+// compact moves the elements to the start of the internal storage, without changing its capacity.
+func (q *RingBuffer[Element]) compact() {
+	if q.front == 0 {
+		return
+	}
+
+	if q.isWrapped() {
+		// rotating the whole storage left by front brings the wrapped elements into order behind the first run.
+		slices.Reverse(q.items[:q.front])
+		slices.Reverse(q.items[q.front:])
+		slices.Reverse(q.items)
+	} else {
+		copy(q.items, q.items[q.front:q.front+q.length])
+	}
+
+	q.front = 0
+}
+
+func (q *RingBuffer[Element]) expand() {
 	if q.length < cap(q.items) {
 		return
 	}
 
-	newCapacity := cap(q.items) * 2
-	newItems := make([]Element, newCapacity)
+	q.grow(q.length + 1)
+}
 
-	copyCount := copy(newItems, q.items[q.front:q.length])
-	if q.front > 0 {
-		copy(newItems[copyCount:], q.items[0:q.front])
+// grow resizes the storage, as determined by the growth policy, so that it can hold at least needed elements.
+func (q *RingBuffer[Element]) grow(needed int) {
+	policy := q.growth
+	if policy == nil {
+		policy = DoublingPolicy{}
 	}
 
+	q.resize(max(policy.NextCapacity(cap(q.items), needed), needed))
+}
+
+// resize moves the elements to the start of new internal storage with the given capacity.
+func (q *RingBuffer[Element]) resize(capacity int) {
+	newItems := make([]Element, capacity)
+	q.PeekInto(newItems)
+
 	q.items = newItems
 	q.front = 0
 }
 
-func (q *ringBufferQueue[Element]) Push(item Element) error {
+func (q *RingBuffer[Element]) Push(item Element) error {
+	recorder := q.options.latencyRecorder
+	if recorder == nil {
+		return q.push(item)
+	}
+
+	start := q.options.clock.Now()
+	if err := q.push(item); err != nil {
+		return err
+	}
+
+	recorder.RecordPush(q.options.clock.Now().Sub(start))
+
+	return nil
+}
+
+func (q *RingBuffer[Element]) push(item Element) error {
 	if q.length == cap(q.items) {
 		if q.bounded {
-			return ErrQueueFull
+			switch q.overflow {
+			case DropOldest:
+				q.discardFront(1)
+			case DropNewest:
+				return nil
+			default:
+				q.rejected++
+				return ErrQueueFull
+			}
+		} else {
+			q.expand()
 		}
-
-		q.expand()
 	}
 
 	back := (q.front + q.length) % cap(q.items)
@@ -101,19 +371,117 @@ func (q *ringBufferQueue[Element]) Push(item Element) error {
 	return nil
 }
 
-func (q *ringBufferQueue[Element]) Pop() (Element, error) {
+// RejectedCount returns the number of times Push has returned the ErrQueueFull error.
+func (q *RingBuffer[Element]) RejectedCount() int {
+	return q.rejected
+}
+
+// pushFront adds an element ahead of the first element, resizing the storage if needed. It must only be used on an
+// unbounded ring buffer.
+func (q *RingBuffer[Element]) pushFront(item Element) {
+	q.expand()
+
+	q.front = (q.front - 1 + cap(q.items)) % cap(q.items)
+	q.items[q.front] = item
+	q.length++
+}
+
+// PushAllAtomic adds all the given elements to the end of the queue, in order, or none of them. If the queue cannot
+// accept all the elements, the ErrQueueFull error is returned and the queue is unchanged.
+func (q *RingBuffer[Element]) PushAllAtomic(items ...Element) error {
+	if free := cap(q.items) - q.length; free < len(items) {
+		if q.bounded {
+			return ErrQueueFull
+		}
+
+		q.grow(q.length + len(items))
+	}
+
+	for _, item := range items {
+		_ = q.Push(item)
+	}
+
+	return nil
+}
+
+// ReplaceAll removes every element of the queue and adds the given elements in their place, in order. If the queue
+// cannot hold all the given elements, the ErrQueueFull error is returned and the queue is left empty.
+func (q *RingBuffer[Element]) ReplaceAll(items []Element) error {
+	q.front, q.length = 0, 0
+
+	if len(items) > cap(q.items) {
+		if q.bounded {
+			return ErrQueueFull
+		}
+
+		q.grow(len(items))
+	}
+
+	q.length = copy(q.items, items)
+
+	return nil
+}
+
+// FillFromChannel receives elements from ch until it is closed, adding each to the end of the queue, and returns
+// the number of elements added. It returns early when the queue cannot accept more elements, without receiving
+// another element from ch.
+func (q *RingBuffer[Element]) FillFromChannel(ch <-chan Element) int {
+	count := 0
+
+	for !q.rejectsPush() {
+		item, ok := <-ch
+		if !ok {
+			break
+		}
+
+		_ = q.Push(item)
+		count++
+	}
+
+	return count
+}
+
+// rejectsPush reports whether the next push will return the ErrQueueFull error.
+func (q *RingBuffer[Element]) rejectsPush() bool {
+	return q.bounded && q.overflow == Reject && q.length == cap(q.items)
+}
+
+func (q *RingBuffer[Element]) Pop() (Element, error) {
+	recorder := q.options.latencyRecorder
+	if recorder == nil {
+		return q.pop()
+	}
+
+	start := q.options.clock.Now()
+	item, err := q.pop()
+	if err != nil {
+		return item, err
+	}
+
+	recorder.RecordPop(q.options.clock.Now().Sub(start))
+
+	return item, nil
+}
+
+// PopWithLen removes and returns the first element of the queue, along with the number of elements that remain.
+// If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *RingBuffer[Element]) PopWithLen() (Element, int, error) {
+	item, err := q.Pop()
+	return item, q.length, err
+}
+
+func (q *RingBuffer[Element]) pop() (Element, error) {
 	item, err := q.Peek()
 	if err != nil {
 		return item, err
 	}
 
-	q.front = (q.front + 1) % cap(q.items)
-	q.length--
+	q.discardFront(1)
 
 	return item, nil
 }
 
-func (q *ringBufferQueue[Element]) Peek() (Element, error) {
+func (q *RingBuffer[Element]) Peek() (Element, error) {
 	var item Element
 
 	if q.length == 0 {
@@ -123,6 +491,865 @@ func (q *ringBufferQueue[Element]) Peek() (Element, error) {
 	return q.items[q.front], nil
 }
 
-func (q *ringBufferQueue[Element]) Length() int {
+// SwapFront replaces the first element of the queue with item and returns the element it replaced.
+// If the queue is empty, the ErrQueueEmpty error is returned and item is not added.
+func (q *RingBuffer[Element]) SwapFront(item Element) (Element, error) {
+	old, err := q.Peek()
+	if err != nil {
+		return old, err
+	}
+
+	q.items[q.front] = item
+
+	return old, nil
+}
+
+// Ends returns the first and last elements of the queue, which are the same element if the queue holds only one.
+// If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *RingBuffer[Element]) Ends() (front, back Element, err error) {
+	front, err = q.Peek()
+	if err != nil {
+		return front, back, err
+	}
+
+	return front, q.at(q.length - 1), nil
+}
+
+// PeekFunc returns the first element of the queue for which pred returns true, without removing it, and true.
+// If no element matches, the zero value and false are returned.
+func (q *RingBuffer[Element]) PeekFunc(pred func(Element) bool) (Element, bool) {
+	for i := range q.length {
+		if item := q.at(i); pred(item) {
+			return item, true
+		}
+	}
+
+	var item Element
+	return item, false
+}
+
+// IndexOf returns the index of the first element of the queue that is equal to target, as determined by the
+// equal function, where index zero is the front. If no element is equal to target, -1 is returned.
+func (q *RingBuffer[Element]) IndexOf(target Element, equal func(a, b Element) bool) int {
+	for i := range q.length {
+		if equal(q.at(i), target) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// PopOr removes and returns the first element of the queue. If the queue is empty, def is returned.
+func (q *RingBuffer[Element]) PopOr(def Element) Element {
+	item, err := q.Pop()
+	if err != nil {
+		return def
+	}
+
+	return item
+}
+
+// PopOrGenerate removes and returns the first element of the queue. If the queue is empty, it returns the result of
+// calling gen, which is not added to the queue. The gen function is only called when the queue is empty.
+func (q *RingBuffer[Element]) PopOrGenerate(gen func() Element) Element {
+	item, err := q.Pop()
+	if err != nil {
+		return gen()
+	}
+
+	return item
+}
+
+// PopIfOver removes and returns the first element of the queue and true, but only if the queue holds more than
+// minLen elements. Otherwise the queue is left unchanged and the zero value and false are returned.
+func (q *RingBuffer[Element]) PopIfOver(minLen int) (Element, bool) {
+	if q.length <= minLen {
+		var item Element
+		return item, false
+	}
+
+	item, err := q.Pop()
+	return item, err == nil
+}
+
+// PeekOr returns the first element of the queue. If the queue is empty, def is returned.
+func (q *RingBuffer[Element]) PeekOr(def Element) Element {
+	item, err := q.Peek()
+	if err != nil {
+		return def
+	}
+
+	return item
+}
+
+// PopUntil removes and returns the elements ahead of the first element for which isDelim returns true, and removes
+// that delimiter element too. It reports whether a delimiter was found. If there is no delimiter, the queue is
+// left unchanged and PopUntil returns nil and false.
+func (q *RingBuffer[Element]) PopUntil(isDelim func(Element) bool) ([]Element, bool) {
+	for i := range q.length {
+		if isDelim(q.at(i)) {
+			items := make([]Element, i)
+			q.PeekInto(items)
+			q.discardFront(i + 1)
+
+			return items, true
+		}
+	}
+
+	return nil, false
+}
+
+// PopRun removes and returns the longest run of elements at the front of the queue that belong to the same group
+// as the first element, as determined by calling sameGroup with the first element and each following element.
+// It returns at least one element unless the queue is empty, in which case it returns nil.
+func (q *RingBuffer[Element]) PopRun(sameGroup func(a, b Element) bool) []Element {
+	if q.length == 0 {
+		return nil
+	}
+
+	count := 1
+	for count < q.length && sameGroup(q.items[q.front], q.at(count)) {
+		count++
+	}
+
+	items := make([]Element, count)
+	q.PeekInto(items)
+	q.discardFront(count)
+
+	return items
+}
+
+// DrainWeighted removes and returns elements from the front of the queue for as long as the sum of their weights,
+// as determined by the weigh function, does not exceed maxWeight. It returns at least one element unless the queue
+// is empty, in which case it returns nil, even if the first element alone weighs more than maxWeight.
+func (q *RingBuffer[Element]) DrainWeighted(maxWeight int, weigh func(Element) int) []Element {
+	if q.length == 0 {
+		return nil
+	}
+
+	count, total := 1, weigh(q.items[q.front])
+	for count < q.length {
+		weight := weigh(q.at(count))
+		if total+weight > maxWeight {
+			break
+		}
+
+		total += weight
+		count++
+	}
+
+	items := make([]Element, count)
+	q.PeekInto(items)
+	q.discardFront(count)
+
+	return items
+}
+
+// PopBackN removes up to n elements from the end of the queue and returns them from back to front, so the last
+// element of the queue comes first. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *RingBuffer[Element]) PopBackN(n int) ([]Element, error) {
+	if q.length == 0 {
+		return nil, ErrQueueEmpty
+	}
+
+	items := make([]Element, max(0, min(n, q.length)))
+	for i := range items {
+		items[i] = q.at(q.length - 1 - i)
+	}
+
+	q.length -= len(items)
+
+	return items, nil
+}
+
+// Skip removes up to n elements from the front of the queue without returning them.
+// It returns the number of elements removed.
+func (q *RingBuffer[Element]) Skip(n int) int {
+	count := max(0, min(n, q.length))
+	q.discardFront(count)
+
+	return count
+}
+
+// Recycle moves up to n elements from the front of the queue to its end, keeping their order, and returns the
+// number of elements moved.
+func (q *RingBuffer[Element]) Recycle(n int) int {
+	count := max(0, min(n, q.length))
+	for range count {
+		item, _ := q.pop()
+		_ = q.push(item)
+	}
+
+	return count
+}
+
+// Remove removes the first element of the queue that is equal to target, as determined by the equal function,
+// moving the elements behind it forward to close the gap. It reports whether an element was removed.
+func (q *RingBuffer[Element]) Remove(target Element, equal func(a, b Element) bool) bool {
+	for i := range q.length {
+		if equal(q.at(i), target) {
+			q.removeAt(i)
+			return true
+		}
+	}
+
+	return false
+}
+
+// DrainExpired removes every element of the queue for which expired returns true, wherever it is in the queue,
+// and returns the removed elements in order. The remaining elements keep their order.
+func (q *RingBuffer[Element]) DrainExpired(expired func(Element) bool) []Element {
+	var drained []Element
+	kept := 0
+	for i := range q.length {
+		item := q.at(i)
+		if expired(item) {
+			drained = append(drained, item)
+			continue
+		}
+
+		q.set(kept, item)
+		kept++
+	}
+
+	q.length = kept
+
+	return drained
+}
+
+// Append pops every element of src and pushes it to the end of this queue, in order, and returns the number of
+// elements moved. If this queue cannot accept more elements, appending stops and the ErrQueueFull error is returned,
+// leaving the element that could not be pushed and those behind it in src. Appending a queue to itself moves nothing
+// and leaves the queue unchanged.
+func (q *RingBuffer[Element]) Append(src Queue[Element]) (int, error) {
+	if self, ok := src.(*RingBuffer[Element]); ok && self == q {
+		return 0, nil
+	}
+
+	count := 0
+
+	for src.Length() > 0 {
+		item, _ := src.Peek()
+		if err := q.Push(item); err != nil {
+			return count, err
+		}
+
+		_, _ = src.Pop()
+		count++
+	}
+
+	return count, nil
+}
+
+// DistributeRoundRobin pops every element of the queue and pushes it to the destination queues in turn, starting
+// with the first. If a destination returns an error, distribution stops and the error is returned, leaving the
+// element that could not be pushed and those behind it in the queue.
+func (q *RingBuffer[Element]) DistributeRoundRobin(dsts ...Queue[Element]) error {
+	if len(dsts) == 0 {
+		return nil
+	}
+
+	for i := 0; q.length > 0; i = (i + 1) % len(dsts) {
+		if err := dsts[i].Push(q.items[q.front]); err != nil {
+			return err
+		}
+
+		_, _ = q.Pop()
+	}
+
+	return nil
+}
+
+// Rebalance moves elements from the front of the longer of this queue and other to the end of the shorter, until
+// their lengths differ by at most one. It stops early if the shorter queue cannot accept more elements.
+func (q *RingBuffer[Element]) Rebalance(other Queue[Element]) {
+	var longer, shorter Queue[Element] = q, other
+	if other.Length() > q.Length() {
+		longer, shorter = other, q
+	}
+
+	for longer.Length()-shorter.Length() > 1 {
+		item, _ := longer.Peek()
+		if shorter.Push(item) != nil {
+			return
+		}
+
+		_, _ = longer.Pop()
+	}
+}
+
+// DrainToChannel pops elements from the front of the queue and sends them to ch, for as long as ch can accept
+// them without blocking. Elements that ch does not accept remain in the queue. It returns the number of elements sent.
+func (q *RingBuffer[Element]) DrainToChannel(ch chan<- Element) int {
+	count := 0
+
+	for q.length > 0 {
+		select {
+		case ch <- q.items[q.front]:
+			_, _ = q.Pop()
+			count++
+		default:
+			return count
+		}
+	}
+
+	return count
+}
+
+// FlushTo pops elements from the front of the queue, in order, and writes each to w with encode. If encode returns
+// an error, flushing stops and the error is returned, leaving the element that failed to encode and those behind it
+// in the queue. It returns the number of elements written.
+func (q *RingBuffer[Element]) FlushTo(w io.Writer, encode func(io.Writer, Element) error) (int, error) {
+	count := 0
+
+	for q.length > 0 {
+		if err := encode(w, q.items[q.front]); err != nil {
+			return count, err
+		}
+
+		_, _ = q.Pop()
+		count++
+	}
+
+	return count, nil
+}
+
+func (q *RingBuffer[Element]) Length() int {
 	return q.length
 }
+
+// Cap returns the number of elements the queue can hold before it is full or, for an unbounded queue, before it
+// must resize its internal storage.
+func (q *RingBuffer[Element]) Cap() int {
+	return cap(q.items)
+}
+
+// UpdateEach calls fn with a pointer to each element of the queue, from front to back, so that fn can modify the
+// elements in place. The pointers refer to the queue's internal storage, so fn must not keep them after it returns,
+// and neither fn nor any other goroutine may use the queue until UpdateEach returns.
+func (q *RingBuffer[Element]) UpdateEach(fn func(*Element)) {
+	for i := range q.length {
+		fn(&q.items[(q.front+i)%cap(q.items)])
+	}
+}
+
+// PeekInto copies up to len(dst) elements from the front of the queue into dst, without removing them.
+// It returns the number of elements copied.
+func (q *RingBuffer[Element]) PeekInto(dst []Element) int {
+	count := min(len(dst), q.length)
+	end := q.front + count
+
+	if end <= cap(q.items) {
+		return copy(dst, q.items[q.front:end])
+	}
+
+	copyCount := copy(dst, q.items[q.front:])
+	return copyCount + copy(dst[copyCount:count], q.items[:end-cap(q.items)])
+}
+
+// All returns an iterator over the elements of the queue, from front to back.
+// The iterator captures the queue's contents when iteration begins, so popping elements from the queue during
+// iteration does not change the elements yielded. Elements pushed during iteration are not yielded, although they
+// may take the place of elements that were popped but not yet yielded.
+func (q *RingBuffer[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		q.yieldFront(q.length, yield)
+	}
+}
+
+// Take returns an iterator over at most the first n elements of the queue, from front to back.
+// It behaves like All when the queue is modified during iteration.
+func (q *RingBuffer[Element]) Take(n int) iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		q.yieldFront(min(n, q.length), yield)
+	}
+}
+
+// yieldFront yields the first count elements, using the storage and front position captured when it is called.
+func (q *RingBuffer[Element]) yieldFront(count int, yield func(Element) bool) {
+	items, front := q.items, q.front
+
+	for i := range count {
+		if !yield(items[(front+i)%cap(items)]) {
+			return
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of the queue, from back to front.
+// It behaves like All when the queue is modified during iteration.
+func (q *RingBuffer[Element]) Backward() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		items, front, length := q.items, q.front, q.length
+
+		for i := length - 1; i >= 0; i-- {
+			if !yield(items[(front+i)%cap(items)]) {
+				return
+			}
+		}
+	}
+}
+
+// MinFunc returns the smallest element of the queue, as determined by the less function.
+// If several elements are equally small, the one nearest the front is returned. If the queue is empty, the
+// ErrQueueEmpty error is returned.
+func (q *RingBuffer[Element]) MinFunc(less func(a, b Element) bool) (Element, error) {
+	item, err := q.Peek()
+	if err != nil {
+		return item, err
+	}
+
+	for i := 1; i < q.length; i++ {
+		if next := q.at(i); less(next, item) {
+			item = next
+		}
+	}
+
+	return item, nil
+}
+
+// MaxFunc returns the largest element of the queue, as determined by the less function.
+// If several elements are equally large, the one nearest the front is returned. If the queue is empty, the
+// ErrQueueEmpty error is returned.
+func (q *RingBuffer[Element]) MaxFunc(less func(a, b Element) bool) (Element, error) {
+	item, err := q.Peek()
+	if err != nil {
+		return item, err
+	}
+
+	for i := 1; i < q.length; i++ {
+		if next := q.at(i); less(item, next) {
+			item = next
+		}
+	}
+
+	return item, nil
+}
+
+// KthSmallest returns the k-th smallest element of the queue, counting from one, as determined by the less function.
+// It selects the element from a copy of the queue's elements without fully sorting it, leaving the queue unchanged.
+// If k is less than one or greater than the length of the queue, the ErrIndexOutOfRange error is returned.
+func (q *RingBuffer[Element]) KthSmallest(k int, less func(a, b Element) bool) (Element, error) {
+	if k < 1 || k > q.length {
+		var item Element
+		return item, ErrIndexOutOfRange
+	}
+
+	items := q.toSlice()
+	target := k - 1
+
+	// quickselect narrows [lo, hi] to the side of each partition that holds the target index.
+	lo, hi := 0, len(items)-1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		items[mid], items[hi] = items[hi], items[mid]
+
+		pivot := lo
+		for i := lo; i < hi; i++ {
+			if less(items[i], items[hi]) {
+				items[i], items[pivot] = items[pivot], items[i]
+				pivot++
+			}
+		}
+
+		items[pivot], items[hi] = items[hi], items[pivot]
+
+		switch {
+		case target < pivot:
+			hi = pivot - 1
+		case target > pivot:
+			lo = pivot + 1
+		default:
+			return items[pivot], nil
+		}
+	}
+
+	return items[target], nil
+}
+
+// IsSorted reports whether the elements of the queue, from front to back, are in order as determined by the less
+// function, so that no element is less than the one before it. An empty queue and a queue of one element are sorted.
+func (q *RingBuffer[Element]) IsSorted(less func(a, b Element) bool) bool {
+	for i := 1; i < q.length; i++ {
+		if less(q.at(i), q.at(i-1)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LongestRun returns the length of the longest run of consecutive elements of the queue in which ordered returns
+// true for each element and the one that follows it. It returns one for a queue whose elements are never ordered,
+// and zero for an empty queue.
+func (q *RingBuffer[Element]) LongestRun(ordered func(a, b Element) bool) int {
+	if q.length == 0 {
+		return 0
+	}
+
+	longest, run := 1, 1
+	for i := 1; i < q.length; i++ {
+		if ordered(q.at(i-1), q.at(i)) {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 1
+		}
+	}
+
+	return longest
+}
+
+// SplitAt returns two new queues, the first holding the elements before index i and the second holding the
+// elements from index i onwards, both in their original order. The new queues have the same capacity and
+// boundedness as this queue, which is left unchanged. If i is outside of [0, Length()], the ErrIndexOutOfRange
+// error is returned.
+func (q *RingBuffer[Element]) SplitAt(i int) (front, back *RingBuffer[Element], err error) {
+	if i < 0 || i > q.length {
+		return nil, nil, ErrIndexOutOfRange
+	}
+
+	frontQueue, backQueue := q.emptyClone(), q.emptyClone()
+
+	for j := range q.length {
+		target := frontQueue
+		if j >= i {
+			target = backQueue
+		}
+
+		target.items[target.length] = q.at(j)
+		target.length++
+	}
+
+	return frontQueue, backQueue, nil
+}
+
+// emptyClone returns an empty queue with the same capacity, boundedness, overflow and growth policies and options as
+// this queue.
+func (q *RingBuffer[Element]) emptyClone() *RingBuffer[Element] {
+	return &RingBuffer[Element]{
+		items:    make([]Element, cap(q.items)),
+		bounded:  q.bounded,
+		growth:   q.growth,
+		overflow: q.overflow,
+		options:  q.options,
+	}
+}
+
+// SetCapacity reallocates the internal storage of the queue to hold exactly n elements, moving the elements to the
+// start of the new storage. For a bounded queue, n becomes its new maximum capacity. If n is less than one or less
+// than the length of the queue, the ErrCapacityTooSmall error is returned and the queue is unchanged. If the queue
+// uses storage supplied by the caller, the ErrFixedStorage error is returned.
+func (q *RingBuffer[Element]) SetCapacity(n int) error {
+	if q.fixed {
+		return ErrFixedStorage
+	}
+
+	if n < max(1, q.length) {
+		return ErrCapacityTooSmall
+	}
+
+	q.resize(n)
+
+	return nil
+}
+
+// Validate checks the internal state of the queue for consistency, returning an error wrapping ErrInvalidQueue
+// that describes the first violation found. It is intended as a debugging aid.
+func (q *RingBuffer[Element]) Validate() error {
+	capacity := cap(q.items)
+
+	// storage of zero capacity, such as an empty backing slice, can only have its front at zero.
+	if q.front < 0 || q.front >= max(capacity, 1) {
+		return fmt.Errorf("%w: front %d is outside of capacity %d", ErrInvalidQueue, q.front, capacity)
+	}
+
+	if q.length < 0 || q.length > capacity {
+		return fmt.Errorf("%w: length %d is outside of capacity %d", ErrInvalidQueue, q.length, capacity)
+	}
+
+	return nil
+}
+
+// SortedSlice returns a new slice holding the elements of the queue, sorted as determined by the less function.
+// Equal elements keep their order in the queue. The queue itself is left unchanged.
+func (q *RingBuffer[Element]) SortedSlice(less func(a, b Element) bool) []Element {
+	items := q.toSlice()
+	slices.SortStableFunc(items, compareFunc(less))
+
+	return items
+}
+
+// Chunks returns the elements of the queue in order, divided into slices of size elements. The final slice holds
+// the remaining elements when the length of the queue is not a multiple of size. The queue itself is left unchanged.
+// Chunks panics if size is less than one.
+func (q *RingBuffer[Element]) Chunks(size int) [][]Element {
+	return slices.Collect(slices.Chunk(q.toSlice(), size))
+}
+
+// Windows returns an iterator over each run of size consecutive elements of the queue, in order, starting with the
+// run at the front and advancing by one element at a time. Each window is a new slice that the consumer may keep.
+// The iterator yields nothing if the queue holds fewer than size elements, and it yields a snapshot of the elements
+// taken when Windows is called. Windows panics if size is less than one.
+func (q *RingBuffer[Element]) Windows(size int) iter.Seq[[]Element] {
+	if size < 1 {
+		panic("queue: window size must be at least one")
+	}
+
+	items := q.toSlice()
+
+	return func(yield func([]Element) bool) {
+		for i := 0; i+size <= len(items); i++ {
+			if !yield(slices.Clone(items[i : i+size])) {
+				return
+			}
+		}
+	}
+}
+
+// compareFunc adapts a less function to the comparison function expected by the slices package.
+func compareFunc[Element any](less func(a, b Element) bool) func(a, b Element) int {
+	return func(a, b Element) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// Checksum returns an FNV-1a hash of the elements of the queue, in order, as formatted by the fmt package's %v verb.
+// Queues holding equal elements in the same order have equal checksums, however their internal storage is laid out.
+func (q *RingBuffer[Element]) Checksum() uint64 {
+	h := fnv.New64a()
+
+	var size [8]byte
+	for i := range q.length {
+		// prefixing each element with its size keeps adjacent elements from running together.
+		formatted := fmt.Sprint(q.at(i))
+		binary.BigEndian.PutUint64(size[:], uint64(len(formatted)))
+		_, _ = h.Write(size[:])
+		_, _ = h.Write([]byte(formatted))
+	}
+
+	return h.Sum64()
+}
+
+// MarshalTail returns the JSON encoding of an array holding the last n elements of the queue, in order, or all the
+// elements if the queue holds fewer than n.
+func (q *RingBuffer[Element]) MarshalTail(n int) ([]byte, error) {
+	start := q.length - max(0, min(n, q.length))
+
+	tail := make([]Element, 0, q.length-start)
+	for i := start; i < q.length; i++ {
+		tail = append(tail, q.at(i))
+	}
+
+	return json.Marshal(tail)
+}
+
+// IsContiguous reports whether the elements of the queue occupy a single contiguous run of its internal storage,
+// rather than wrapping around from the end of the storage to its start.
+func (q *RingBuffer[Element]) IsContiguous() bool {
+	return !q.isWrapped()
+}
+
+// Freeze returns a read-only view of the elements of the queue. The view shares the queue's internal storage
+// rather than copying it, so it is only guaranteed to reflect the frozen elements while the queue is not modified.
+func (q *RingBuffer[Element]) Freeze() ReadOnlyQueue[Element] {
+	return readOnlyRingBuffer[Element]{
+		q: &RingBuffer[Element]{
+			items:   q.items,
+			front:   q.front,
+			length:  q.length,
+			bounded: true,
+		},
+	}
+}
+
+// readOnlyRingBuffer restricts a ring buffer to the methods of ReadOnlyQueue.
+type readOnlyRingBuffer[Element any] struct {
+	q *RingBuffer[Element]
+}
+
+func (r readOnlyRingBuffer[Element]) Peek() (Element, error) {
+	return r.q.Peek()
+}
+
+func (r readOnlyRingBuffer[Element]) At(i int) (Element, error) {
+	if i < 0 || i >= r.q.length {
+		var item Element
+		return item, ErrIndexOutOfRange
+	}
+
+	return r.q.at(i), nil
+}
+
+func (r readOnlyRingBuffer[Element]) Length() int {
+	return r.q.length
+}
+
+func (r readOnlyRingBuffer[Element]) All() iter.Seq[Element] {
+	return r.q.All()
+}
+
+// SharesBacking reports whether a and b are ring buffers, or views returned by the Freeze method of ring buffers, that
+// use the same internal storage. It is intended for tests checking whether an operation copies elements. It returns
+// false if either queue is not a ring buffer or a view of one, or has no storage.
+func SharesBacking[Element any](a, b elementSource[Element]) bool {
+	ra, ok := backingRingBuffer(a)
+	if !ok {
+		return false
+	}
+
+	rb, ok := backingRingBuffer(b)
+	if !ok {
+		return false
+	}
+
+	return cap(ra.items) > 0 && cap(rb.items) > 0 && unsafe.SliceData(ra.items) == unsafe.SliceData(rb.items)
+}
+
+// elementSource is satisfied by both Queue and ReadOnlyQueue.
+type elementSource[Element any] interface {
+	Length() int
+	All() iter.Seq[Element]
+}
+
+func backingRingBuffer[Element any](q elementSource[Element]) (*RingBuffer[Element], bool) {
+	switch q := q.(type) {
+	case *RingBuffer[Element]:
+		return q, true
+	case readOnlyRingBuffer[Element]:
+		return q.q, true
+	default:
+		return nil, false
+	}
+}
+
+// Cycle returns an iterator that yields the elements of the queue from front to back, then starts again from the
+// front, and repeats until the consumer stops. The iterator yields a snapshot of the elements taken when Cycle is
+// called and is unaffected by later changes to the queue. If the queue is empty, the iterator yields nothing.
+func (q *RingBuffer[Element]) Cycle() iter.Seq[Element] {
+	items := q.toSlice()
+
+	return func(yield func(Element) bool) {
+		if len(items) == 0 {
+			return
+		}
+
+		for {
+			for _, item := range items {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// PrepareForBatch makes room for n more elements and returns the free storage behind the last element, so that a
+// caller can write elements into it directly rather than pushing them one at a time. An unbounded ring buffer grows
+// as needed to fit n elements, while a bounded one returns fewer than n slots if it lacks room. The written elements
+// become part of the queue once CommitBatch is called. Any other use of the ring buffer invalidates the returned slice.
+// It panics if n is negative.
+func (q *RingBuffer[Element]) PrepareForBatch(n int) []Element {
+	if n < 0 {
+		panic("queue: batch size out of range")
+	}
+
+	if free := cap(q.items) - q.length; !q.bounded && free < n {
+		q.grow(q.length + n)
+	}
+
+	q.compact()
+
+	return q.items[q.length : q.length+min(n, cap(q.items)-q.length)]
+}
+
+// CommitBatch adds count elements, written into the slice returned by PrepareForBatch or into the spans returned by
+// FreeSpans, to the end of the queue.
+// It panics if count is negative or exceeds the free capacity of the ring buffer.
+func (q *RingBuffer[Element]) CommitBatch(count int) {
+	if count < 0 || q.length+count > cap(q.items) {
+		panic("queue: batch count out of range")
+	}
+
+	q.length += count
+}
+
+// BackIndex returns the index in the internal storage of the ring buffer at which the next pushed element would be
+// stored, which is where the first span returned by FreeSpans starts. Elements written there and after it can be added
+// to the queue with Advance.
+func (q *RingBuffer[Element]) BackIndex() int {
+	if cap(q.items) == 0 {
+		return 0
+	}
+
+	return (q.front + q.length) % cap(q.items)
+}
+
+// Advance adds n elements, written directly into the internal storage from BackIndex onwards, to the end of the queue.
+// It is equivalent to CommitBatch, and panics if n is negative or exceeds the free capacity of the ring buffer.
+func (q *RingBuffer[Element]) Advance(n int) {
+	q.CommitBatch(n)
+}
+
+// WillResizeOnPush reports whether the next push will resize the internal storage of an unbounded ring buffer,
+// letting callers grow it ahead of time with SetCapacity. It always returns false for a bounded ring buffer.
+func (q *RingBuffer[Element]) WillResizeOnPush() bool {
+	return !q.bounded && q.length == cap(q.items)
+}
+
+// DebugState returns a snapshot of the internal state of the ring buffer: the storage index of the first element,
+// the number of elements, the capacity of the storage and whether the ring buffer is bounded.
+// It is intended as a diagnostic aid, and its results are not part of the stable API.
+func (q *RingBuffer[Element]) DebugState() (front, length, capacity int, bounded bool) {
+	return q.front, q.length, cap(q.items), q.bounded
+}
+
+// EstimatedBytes returns a rough estimate of the memory used by the internal storage of the ring buffer, which is its
+// capacity multiplied by the size of an element. It ignores any memory that elements refer to, such as the contents of
+// strings, slices or maps, and the ring buffer's own bookkeeping.
+func (q *RingBuffer[Element]) EstimatedBytes() int {
+	var item Element
+	return cap(q.items) * int(unsafe.Sizeof(item))
+}
+
+// Slack returns the number of unused element slots in the internal storage of the ring buffer, which is its capacity
+// less its length, for bounded and unbounded ring buffers alike. A pool can use it to judge whether a ring buffer
+// holds enough spare storage to be worth retaining for reuse.
+func (q *RingBuffer[Element]) Slack() int {
+	return cap(q.items) - q.length
+}
+
+// FreeSpans returns the free storage of the ring buffer, in the order that pushed elements would fill it, so that a
+// caller can write elements into it directly. The first span follows the last element. The second span, which is
+// empty unless the first span reaches the end of the storage, is at the start of the storage, ahead of the first
+// element. The written elements become part of the queue once CommitBatch is called, and they must fill the first
+// span before any are written to the second. Any other use of the ring buffer invalidates the returned spans.
+func (q *RingBuffer[Element]) FreeSpans() (first []Element, second []Element) {
+	back := q.front + q.length
+	if back >= cap(q.items) {
+		return q.items[back-cap(q.items) : q.front], nil
+	}
+
+	return q.items[back:], q.items[:q.front]
+}
+
+// DataSpans returns the elements of the ring buffer as up to two slices of its internal storage, which together hold
+// the elements in order, so that a caller can read them without copying. The second span is empty unless the elements
+// wrap around from the end of the storage to its start. Any modification of the ring buffer invalidates the returned
+// spans.
+func (q *RingBuffer[Element]) DataSpans() (first []Element, second []Element) {
+	back := q.front + q.length
+	if back > cap(q.items) {
+		return q.items[q.front:], q.items[:back-cap(q.items)]
+	}
+
+	return q.items[q.front:back], nil
+}
@@ -1,13 +1,43 @@
 package queue
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"math/rand/v2"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func runCommonQueueTests(t *testing.T, createQueue func(capacity int) Queue[int]) {
+// createWrappedQueue returns a queue holding items whose contents wrap around the end of its internal storage.
+func createWrappedQueue(t *testing.T, createQueue func(capacity int) *RingBuffer[int], items ...int) *RingBuffer[int] {
+	t.Helper()
+
+	q := createQueue(len(items))
+	offset := max(1, len(items)/2)
+
+	for range offset {
+		assert.NoError(t, q.Push(0))
+	}
+
+	for range offset {
+		_, err := q.Pop()
+		assert.NoError(t, err)
+	}
+
+	for _, item := range items {
+		assert.NoError(t, q.Push(item))
+	}
+
+	return q
+}
+
+func runCommonQueueTests(t *testing.T, createQueue func(capacity int) *RingBuffer[int]) {
 	t.Helper()
 
 	t.Run("new queue has zero length", func(t *testing.T) {
@@ -15,6 +45,11 @@ func runCommonQueueTests(t *testing.T, createQueue func(capacity int) Queue[int]
 		assert.Equal(t, 0, q.Length())
 	})
 
+	t.Run("new queue has given capacity", func(t *testing.T) {
+		assert.Equal(t, 3, createQueue(3).Cap())
+		assert.Equal(t, defaultRingBufferQueueCapacity, createQueue(0).Cap())
+	})
+
 	t.Run("cannot pop from empty queue", func(t *testing.T) {
 		q := createQueue(1)
 		_, err := q.Pop()
@@ -68,6 +103,378 @@ func runCommonQueueTests(t *testing.T, createQueue func(capacity int) Queue[int]
 		assert.Equal(t, x, y)
 	})
 
+	t.Run("swap front replaces first element", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+
+		old, err := q.SwapFront(10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, old)
+		assert.Equal(t, 3, q.Length())
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 10, x)
+
+		_, err = createQueue(1).SwapFront(10)
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("ends returns first and last elements", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+
+		front, back, err := q.Ends()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, front)
+		assert.Equal(t, 4, back)
+
+		q = createQueue(1)
+		assert.NoError(t, q.Push(7))
+		front, back, err = q.Ends()
+		assert.NoError(t, err)
+		assert.Equal(t, 7, front)
+		assert.Equal(t, 7, back)
+
+		_, _, err = createQueue(1).Ends()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("peek func returns first matching element", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 3, 5, 6, 7, 8)
+
+		x, found := q.PeekFunc(func(x int) bool { return x%2 == 0 })
+		assert.True(t, found)
+		assert.Equal(t, 6, x)
+		assert.Equal(t, 6, q.Length())
+
+		x, found = q.PeekFunc(func(x int) bool { return x > 10 })
+		assert.False(t, found)
+		assert.Equal(t, 0, x)
+	})
+
+	t.Run("index of finds first equal element", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 4, 5, 6, 7, 6)
+		equal := func(a, b int) bool { return a == b }
+
+		assert.Equal(t, 0, q.IndexOf(4, equal))
+		assert.Equal(t, 2, q.IndexOf(6, equal))
+		assert.Equal(t, 3, q.IndexOf(7, equal))
+		assert.Equal(t, -1, q.IndexOf(8, equal))
+		assert.Equal(t, -1, createQueue(1).IndexOf(4, equal))
+	})
+
+	t.Run("pop or and peek or return default for empty queue", func(t *testing.T) {
+		q := createQueue(1)
+		assert.Equal(t, -1, q.PeekOr(-1))
+		assert.Equal(t, -1, q.PopOr(-1))
+	})
+
+	t.Run("pop or generate only generates for empty queue", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 4)
+
+		calls := 0
+		gen := func() int {
+			calls++
+			return -1
+		}
+
+		assert.Equal(t, 4, q.PopOrGenerate(gen))
+		assert.Equal(t, 0, calls)
+
+		assert.Equal(t, -1, q.PopOrGenerate(gen))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 0, q.Length())
+	})
+
+	t.Run("pop if over keeps minimum length", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+
+		x, popped := q.PopIfOver(3)
+		assert.False(t, popped)
+		assert.Equal(t, 0, x)
+		assert.Equal(t, 3, q.Length())
+
+		x, popped = q.PopIfOver(2)
+		assert.True(t, popped)
+		assert.Equal(t, 1, x)
+		assert.Equal(t, 2, q.Length())
+
+		_, popped = createQueue(1).PopIfOver(0)
+		assert.False(t, popped)
+	})
+
+	t.Run("pop or and peek or return front element", func(t *testing.T) {
+		q := createQueue(2)
+		assert.NoError(t, q.Push(10))
+		assert.NoError(t, q.Push(20))
+
+		assert.Equal(t, 10, q.PeekOr(-1))
+		assert.Equal(t, 2, q.Length())
+
+		assert.Equal(t, 10, q.PopOr(-1))
+		assert.Equal(t, 1, q.Length())
+	})
+
+	t.Run("pop until consumes elements through delimiter", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 0, 3, 0, 4)
+		isDelim := func(x int) bool { return x == 0 }
+
+		items, found := q.PopUntil(isDelim)
+		assert.True(t, found)
+		assert.Equal(t, []int{1, 2}, items)
+
+		items, found = q.PopUntil(isDelim)
+		assert.True(t, found)
+		assert.Equal(t, []int{3}, items)
+
+		items, found = q.PopUntil(isDelim)
+		assert.False(t, found)
+		assert.Nil(t, items)
+		assert.Equal(t, []int{4}, slices.Collect(q.All()))
+	})
+
+	t.Run("pop until with leading delimiter", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 0, 1)
+
+		items, found := q.PopUntil(func(x int) bool { return x == 0 })
+		assert.True(t, found)
+		assert.Empty(t, items)
+		assert.Equal(t, []int{1}, slices.Collect(q.All()))
+	})
+
+	t.Run("pop run removes runs of same group", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 3, 5, 2, 4, 7)
+		sameParity := func(a, b int) bool { return a%2 == b%2 }
+
+		assert.Equal(t, []int{1, 3, 5}, q.PopRun(sameParity))
+		assert.Equal(t, []int{2, 4}, q.PopRun(sameParity))
+		assert.Equal(t, []int{7}, q.PopRun(sameParity))
+		assert.Nil(t, q.PopRun(sameParity))
+	})
+
+	t.Run("skip removes front elements", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+		assert.Equal(t, 2, q.Skip(2))
+		assert.Equal(t, []int{3, 4, 5}, slices.Collect(q.All()))
+
+		assert.Equal(t, 3, q.Skip(3))
+		assert.Equal(t, 0, q.Length())
+
+		q = createWrappedQueue(t, createQueue, 1, 2, 3)
+		assert.Equal(t, 3, q.Skip(10))
+		assert.Equal(t, 0, q.Length())
+		assert.Equal(t, 0, q.Skip(1))
+
+		assert.NoError(t, q.Push(6))
+		assert.Equal(t, []int{6}, slices.Collect(q.All()))
+	})
+
+	t.Run("remove deletes first matching element", func(t *testing.T) {
+		equal := func(a, b int) bool { return a == b }
+
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5, 2)
+		assert.True(t, q.Remove(1, equal))
+		assert.Equal(t, []int{2, 3, 4, 5, 2}, slices.Collect(q.All()))
+
+		assert.True(t, q.Remove(2, equal))
+		assert.Equal(t, []int{3, 4, 5, 2}, slices.Collect(q.All()))
+
+		q = createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5, 6)
+		assert.True(t, q.Remove(3, equal))
+		assert.Equal(t, []int{1, 2, 4, 5, 6}, slices.Collect(q.All()))
+
+		assert.True(t, q.Remove(5, equal))
+		assert.Equal(t, []int{1, 2, 4, 6}, slices.Collect(q.All()))
+
+		assert.NoError(t, q.Push(7))
+		assert.Equal(t, []int{1, 2, 4, 6, 7}, slices.Collect(q.All()))
+	})
+
+	t.Run("remove without match leaves queue unchanged", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+		assert.False(t, q.Remove(9, func(a, b int) bool { return a == b }))
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+	})
+
+	t.Run("drain weighted stops before exceeding weight", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 3, 4, 2, 9, 1)
+		weigh := func(x int) int { return x }
+
+		assert.Equal(t, []int{3, 4, 2}, q.DrainWeighted(10, weigh))
+		assert.Equal(t, []int{9}, q.DrainWeighted(5, weigh))
+		assert.Equal(t, []int{1}, q.DrainWeighted(5, weigh))
+		assert.Nil(t, q.DrainWeighted(5, weigh))
+	})
+
+	t.Run("pop back n removes elements from the end", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+
+		items, err := q.PopBackN(2)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{5, 4}, items)
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+
+		items, err = q.PopBackN(5)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 2, 1}, items)
+
+		_, err = q.PopBackN(1)
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("recycle moves front elements to back", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+
+		assert.Equal(t, 2, q.Recycle(2))
+		assert.Equal(t, []int{3, 4, 5, 1, 2}, slices.Collect(q.All()))
+
+		assert.Equal(t, 5, q.Recycle(7))
+		assert.Equal(t, []int{3, 4, 5, 1, 2}, slices.Collect(q.All()))
+		assert.Equal(t, 0, createQueue(1).Recycle(1))
+	})
+
+	t.Run("drain expired removes matching elements throughout queue", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5, 6, 7)
+
+		drained := q.DrainExpired(func(x int) bool { return x%3 != 1 })
+		assert.Equal(t, []int{2, 3, 5, 6}, drained)
+		assert.Equal(t, []int{1, 4, 7}, slices.Collect(q.All()))
+
+		assert.Empty(t, q.DrainExpired(func(x int) bool { return x > 10 }))
+		assert.Equal(t, 3, q.Length())
+	})
+
+	t.Run("append moves all elements of source", func(t *testing.T) {
+		q := createQueue(5)
+		assert.NoError(t, q.Push(1))
+		src := createWrappedQueue(t, createQueue, 2, 3, 4)
+
+		count, err := q.Append(src)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(q.All()))
+		assert.Equal(t, 0, src.Length())
+	})
+
+	t.Run("append to itself leaves queue unchanged", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+
+		count, err := q.Append(q)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+	})
+
+	t.Run("distribute round robin drains into destinations in turn", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5, 6)
+		dsts := []Queue[int]{createQueue(2), createQueue(2), createQueue(2)}
+
+		assert.NoError(t, q.DistributeRoundRobin(dsts...))
+		assert.Equal(t, 0, q.Length())
+		assert.Equal(t, []int{1, 4}, slices.Collect(dsts[0].All()))
+		assert.Equal(t, []int{2, 5}, slices.Collect(dsts[1].All()))
+		assert.Equal(t, []int{3, 6}, slices.Collect(dsts[2].All()))
+	})
+
+	t.Run("distribute round robin stops at full destination", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+		unbounded := NewUnboundedQueue[int](1)
+		bounded := NewBoundedQueue[int](1)
+
+		assert.ErrorIs(t, q.DistributeRoundRobin(unbounded, bounded), ErrQueueFull)
+		assert.Equal(t, []int{1, 3}, slices.Collect(unbounded.All()))
+		assert.Equal(t, []int{2}, slices.Collect(bounded.All()))
+		assert.Equal(t, []int{4}, slices.Collect(q.All()))
+	})
+
+	t.Run("rebalance evens out lengths", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5, 6)
+		other := createQueue(6)
+
+		q.Rebalance(other)
+		assert.Equal(t, []int{4, 5, 6}, slices.Collect(q.All()))
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(other.All()))
+
+		other.Rebalance(q)
+		assert.Equal(t, 3, q.Length())
+
+		assert.NoError(t, other.Push(7))
+		assert.NoError(t, other.Push(8))
+		other.Rebalance(q)
+		assert.Equal(t, []int{4, 5, 6, 1}, slices.Collect(q.All()))
+		assert.Equal(t, []int{2, 3, 7, 8}, slices.Collect(other.All()))
+	})
+
+	t.Run("rebalance stops when shorter queue is full", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5, 6)
+		other := NewBoundedQueue[int](1)
+
+		q.Rebalance(other)
+		assert.Equal(t, []int{2, 3, 4, 5, 6}, slices.Collect(q.All()))
+		assert.Equal(t, []int{1}, slices.Collect(other.All()))
+	})
+
+	t.Run("drain to channel sends only what channel accepts", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+		ch := make(chan int, 3)
+
+		assert.Equal(t, 3, q.DrainToChannel(ch))
+		assert.Equal(t, 2, q.Length())
+		assert.Equal(t, 0, q.DrainToChannel(ch))
+
+		close(ch)
+		var got []int
+		for x := range ch {
+			got = append(got, x)
+		}
+
+		assert.Equal(t, []int{1, 2, 3}, got)
+		assert.Equal(t, []int{4, 5}, slices.Collect(q.All()))
+	})
+
+	t.Run("drain to channel empties queue when channel has room", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2)
+		ch := make(chan int, 5)
+
+		assert.Equal(t, 2, q.DrainToChannel(ch))
+		assert.Equal(t, 0, q.Length())
+		assert.Len(t, ch, 2)
+	})
+
+	t.Run("flush to writes elements until encode fails", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+		errEncode := errors.New("cannot encode")
+
+		var buf bytes.Buffer
+		count, err := q.FlushTo(&buf, func(w io.Writer, x int) error {
+			if x == 3 {
+				return errEncode
+			}
+
+			_, err := fmt.Fprintln(w, x)
+			return err
+		})
+
+		assert.ErrorIs(t, err, errEncode)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, "1\n2\n", buf.String())
+		assert.Equal(t, []int{3, 4}, slices.Collect(q.All()))
+	})
+
+	t.Run("flush to empties queue", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2)
+
+		var buf bytes.Buffer
+		count, err := q.FlushTo(&buf, func(w io.Writer, x int) error {
+			_, err := fmt.Fprintln(w, x)
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, "1\n2\n", buf.String())
+		assert.Equal(t, 0, q.Length())
+	})
+
 	t.Run("pop returns item in front of queue", func(t *testing.T) {
 		q := createQueue(2)
 		assert.NoError(t, q.Push(10))
@@ -78,6 +485,21 @@ func runCommonQueueTests(t *testing.T, createQueue func(capacity int) Queue[int]
 		assert.Equal(t, 10, x)
 	})
 
+	t.Run("pop with len returns remaining length", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+
+		for i := range 3 {
+			x, length, err := q.PopWithLen()
+			assert.NoError(t, err)
+			assert.Equal(t, i+1, x)
+			assert.Equal(t, 2-i, length)
+		}
+
+		_, length, err := q.PopWithLen()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+		assert.Equal(t, 0, length)
+	})
+
 	t.Run("peek returns item in front of queue", func(t *testing.T) {
 		q := createQueue(2)
 		assert.NoError(t, q.Push(10))
@@ -106,86 +528,1145 @@ func runCommonQueueTests(t *testing.T, createQueue func(capacity int) Queue[int]
 		assert.NoError(t, err)
 		assert.Equal(t, 30, x)
 	})
-}
-
-func runBoundedQueueTests(t *testing.T, createQueue func(capacity int) Queue[int]) {
-	t.Helper()
 
-	runCommonQueueTests(t, createQueue)
+	t.Run("update each modifies elements in place", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
 
-	t.Run("cannot push to full queue", func(t *testing.T) {
-		q := createQueue(1)
-		assert.NoError(t, q.Push(1))
+		q.UpdateEach(func(x *int) { *x *= 10 })
+		assert.Equal(t, []int{10, 20, 30, 40, 50}, slices.Collect(q.All()))
 
-		err := q.Push(2)
-		assert.ErrorIs(t, err, ErrQueueFull)
+		createQueue(1).UpdateEach(func(*int) { t.Fatal("called for empty queue") })
 	})
-}
 
-func runUnboundedQueueTests(t *testing.T, createQueue func(capacity int) Queue[int]) {
-	t.Helper()
+	t.Run("peek into copies front elements without removing them", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
 
-	runCommonQueueTests(t, createQueue)
+		dst := make([]int, 3)
+		assert.Equal(t, 3, q.PeekInto(dst))
+		assert.Equal(t, []int{1, 2, 3}, dst)
+		assert.Equal(t, 5, q.Length())
 
-	t.Run("resize after pushing with no pops", func(t *testing.T) {
+		dst = make([]int, 7)
+		assert.Equal(t, 5, q.PeekInto(dst))
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 0, 0}, dst)
+		assert.Equal(t, 5, q.Length())
+	})
+
+	t.Run("peek into empty queue copies nothing", func(t *testing.T) {
 		q := createQueue(2)
-		const itemCount = 4
+		assert.Equal(t, 0, q.PeekInto(make([]int, 2)))
+	})
 
-		for i := range itemCount {
-			assert.NoError(t, q.Push(i+1))
-		}
+	t.Run("min and max of empty queue", func(t *testing.T) {
+		q := createQueue(2)
+		less := func(a, b int) bool { return a < b }
 
-		for i := range itemCount {
-			x, err := q.Pop()
+		_, err := q.MinFunc(less)
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, err = q.MaxFunc(less)
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("min and max scan all elements", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 4, 9, 2, 7, 1, 8)
+		less := func(a, b int) bool { return a < b }
+
+		x, err := q.MinFunc(less)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+
+		x, err = q.MaxFunc(less)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, x)
+		assert.Equal(t, 6, q.Length())
+	})
+
+	t.Run("kth smallest selects by rank", func(t *testing.T) {
+		less := func(a, b int) bool { return a < b }
+		q := createWrappedQueue(t, createQueue, 7, 3, 9, 1, 3, 8)
+
+		for k, want := range []int{1, 3, 3, 7, 8, 9} {
+			x, err := q.KthSmallest(k+1, less)
 			assert.NoError(t, err)
-			assert.Equal(t, i+1, x)
+			assert.Equal(t, want, x, "k=%d", k+1)
 		}
+
+		assert.Equal(t, []int{7, 3, 9, 1, 3, 8}, slices.Collect(q.All()))
+
+		_, err := q.KthSmallest(0, less)
+		assert.ErrorIs(t, err, ErrIndexOutOfRange)
+
+		_, err = q.KthSmallest(7, less)
+		assert.ErrorIs(t, err, ErrIndexOutOfRange)
 	})
 
-	t.Run("resize after pushing with pop", func(t *testing.T) {
-		t.Run("initial capacity of two", func(t *testing.T) {
-			q := createQueue(2)
-			assert.NoError(t, q.Push(1))
-			assert.NoError(t, q.Push(2))
-			_, _ = q.Pop()
-			assert.NoError(t, q.Push(3))
-			assert.NoError(t, q.Push(4))
+	t.Run("is sorted checks order of all elements", func(t *testing.T) {
+		less := func(a, b int) bool { return a < b }
 
-			for i := range 3 {
-				x, err := q.Pop()
-				assert.NoError(t, err)
-				assert.Equal(t, i+2, x)
-			}
-		})
+		assert.True(t, createWrappedQueue(t, createQueue, 1, 2, 2, 5, 8).IsSorted(less))
+		assert.False(t, createWrappedQueue(t, createQueue, 1, 2, 5, 4, 8).IsSorted(less))
+		assert.False(t, createWrappedQueue(t, createQueue, 1, 2, 5, 8, 7).IsSorted(less))
+		assert.True(t, createWrappedQueue(t, createQueue, 3).IsSorted(less))
+		assert.True(t, createQueue(1).IsSorted(less))
+	})
 
-		t.Run("initial capacity of five", func(t *testing.T) {
-			q := createQueue(5)
-			assert.NoError(t, q.Push(1))
-			assert.NoError(t, q.Push(2))
-			assert.NoError(t, q.Push(3))
-			assert.NoError(t, q.Push(4))
-			assert.NoError(t, q.Push(5))
-			_, _ = q.Pop()
-			_, _ = q.Pop()
-			_, _ = q.Pop()
-			assert.NoError(t, q.Push(6))
-			assert.NoError(t, q.Push(7))
-			assert.NoError(t, q.Push(8))
-			assert.NoError(t, q.Push(9))
+	t.Run("longest run finds longest ordered run", func(t *testing.T) {
+		less := func(a, b int) bool { return a < b }
 
-			for i := range 5 {
-				x, err := q.Pop()
-				assert.NoError(t, err)
-				assert.Equal(t, i+4, x)
-			}
-		})
+		q := createWrappedQueue(t, createQueue, 3, 1, 2, 4, 7, 5, 6, 8)
+		assert.Equal(t, 4, q.LongestRun(less))
+		assert.Equal(t, 8, q.Length())
+
+		assert.Equal(t, 1, createWrappedQueue(t, createQueue, 3, 2, 1).LongestRun(less))
+		assert.Equal(t, 0, createQueue(1).LongestRun(less))
 	})
-}
 
-func TestBoundedRingBufferQueue(t *testing.T) {
-	runBoundedQueueTests(t, newBoundedRingBufferQueue[int])
-}
+	t.Run("all yields nothing for empty queue", func(t *testing.T) {
+		q := createQueue(2)
+		assert.Empty(t, slices.Collect(q.All()))
+		assert.Empty(t, slices.Collect(q.Backward()))
+	})
 
-func TestUnboundedRingBufferQueue(t *testing.T) {
-	runUnboundedQueueTests(t, newUnboundedRingBufferQueue[int])
+	t.Run("all yields elements from front to back", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(q.All()))
+	})
+
+	t.Run("backward yields elements from back to front", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+		assert.Equal(t, []int{5, 4, 3, 2, 1}, slices.Collect(q.Backward()))
+	})
+
+	t.Run("take yields at most n front elements", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.Take(3)))
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(q.Take(8)))
+		assert.Empty(t, slices.Collect(q.Take(0)))
+		assert.Equal(t, 5, q.Length())
+	})
+
+	t.Run("all stops when consumer stops", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+
+		var got []int
+		for x := range q.All() {
+			if x == 3 {
+				break
+			}
+			got = append(got, x)
+		}
+
+		assert.Equal(t, []int{1, 2}, got)
+	})
+
+	t.Run("split at divides elements between new queues", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5, 6)
+
+		front, back, err := q.SplitAt(0)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, front.Length())
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, slices.Collect(back.All()))
+
+		front, back, err = q.SplitAt(6)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, slices.Collect(front.All()))
+		assert.Equal(t, 0, back.Length())
+
+		front, back, err = q.SplitAt(4)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(front.All()))
+		assert.Equal(t, []int{5, 6}, slices.Collect(back.All()))
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, slices.Collect(q.All()))
+	})
+
+	t.Run("split queues are independent of original", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+
+		front, back, err := q.SplitAt(2)
+		assert.NoError(t, err)
+		assert.NoError(t, front.Push(5))
+		_, _ = back.Pop()
+
+		assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(q.All()))
+		assert.Equal(t, []int{1, 2, 5}, slices.Collect(front.All()))
+		assert.Equal(t, []int{4}, slices.Collect(back.All()))
+	})
+
+	t.Run("cannot split outside of queue", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+
+		_, _, err := q.SplitAt(-1)
+		assert.ErrorIs(t, err, ErrIndexOutOfRange)
+
+		_, _, err = q.SplitAt(5)
+		assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	})
+
+	t.Run("set capacity grows storage", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+		assert.NoError(t, q.SetCapacity(10))
+		assert.Equal(t, 10, q.Cap())
+		assert.True(t, q.IsContiguous())
+		assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(q.All()))
+
+		for i := range 6 {
+			assert.NoError(t, q.Push(i+5))
+		}
+		assert.Equal(t, 10, q.Length())
+	})
+
+	t.Run("set capacity shrinks storage to length", func(t *testing.T) {
+		q := createQueue(8)
+		for i := range 3 {
+			assert.NoError(t, q.Push(i+1))
+		}
+
+		assert.NoError(t, q.SetCapacity(3))
+		assert.Equal(t, 3, q.Cap())
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+	})
+
+	t.Run("cannot set capacity below length", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+		assert.ErrorIs(t, q.SetCapacity(3), ErrCapacityTooSmall)
+		assert.Equal(t, 4, q.Cap())
+		assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(q.All()))
+
+		assert.ErrorIs(t, createQueue(2).SetCapacity(0), ErrCapacityTooSmall)
+	})
+
+	t.Run("valid queue passes validation", func(t *testing.T) {
+		assert.NoError(t, createQueue(2).Validate())
+		assert.NoError(t, createWrappedQueue(t, createQueue, 1, 2, 3, 4).Validate())
+	})
+
+	t.Run("sorted slice leaves queue order unchanged", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 5, 3, 6, 1, 4, 2)
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, q.SortedSlice(func(a, b int) bool { return a < b }))
+		assert.Equal(t, []int{5, 3, 6, 1, 4, 2}, slices.Collect(q.All()))
+		assert.Empty(t, createQueue(2).SortedSlice(func(a, b int) bool { return a < b }))
+	})
+
+	t.Run("chunks divides elements in order", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5, 6)
+
+		assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5, 6}}, q.Chunks(2))
+		assert.Equal(t, [][]int{{1, 2, 3, 4}, {5, 6}}, q.Chunks(4))
+		assert.Equal(t, [][]int{{1, 2, 3, 4, 5, 6}}, q.Chunks(10))
+		assert.Equal(t, 6, q.Length())
+
+		assert.Empty(t, createQueue(2).Chunks(3))
+		assert.Panics(t, func() { q.Chunks(0) })
+	})
+
+	t.Run("windows yields overlapping runs of elements", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+
+		windows := slices.Collect(q.Windows(3))
+		assert.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, windows)
+
+		windows[0][0] = 9
+		assert.Equal(t, []int{2, 3, 4}, windows[1])
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(q.All()))
+
+		assert.Empty(t, slices.Collect(q.Windows(6)))
+		assert.Panics(t, func() { q.Windows(0) })
+	})
+
+	t.Run("checksum depends only on elements and their order", func(t *testing.T) {
+		contiguous := createQueue(4)
+		for i := range 4 {
+			assert.NoError(t, contiguous.Push(i+1))
+		}
+
+		wrapped := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+		assert.Equal(t, contiguous.Checksum(), wrapped.Checksum())
+
+		reordered := createWrappedQueue(t, createQueue, 2, 1, 3, 4)
+		assert.NotEqual(t, contiguous.Checksum(), reordered.Checksum())
+
+		joined := createWrappedQueue(t, createQueue, 12, 3, 4)
+		split := createWrappedQueue(t, createQueue, 1, 23, 4)
+		assert.NotEqual(t, joined.Checksum(), split.Checksum())
+	})
+
+	t.Run("marshal tail encodes most recent elements", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+
+		data, err := q.MarshalTail(3)
+		assert.NoError(t, err)
+
+		var tail []int
+		assert.NoError(t, json.Unmarshal(data, &tail))
+		assert.Equal(t, []int{3, 4, 5}, tail)
+
+		data, err = q.MarshalTail(10)
+		assert.NoError(t, err)
+		assert.JSONEq(t, "[1, 2, 3, 4, 5]", string(data))
+
+		data, err = q.MarshalTail(0)
+		assert.NoError(t, err)
+		assert.JSONEq(t, "[]", string(data))
+		assert.Equal(t, 5, q.Length())
+	})
+
+	t.Run("wrapped queue is not contiguous", func(t *testing.T) {
+		q := createQueue(2)
+		assert.True(t, q.IsContiguous())
+
+		q = createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+		assert.False(t, q.IsContiguous())
+	})
+
+	t.Run("freeze returns read-only view of elements", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4)
+		frozen := q.Freeze()
+
+		_, isQueue := frozen.(Queue[int])
+		assert.False(t, isQueue)
+
+		assert.Equal(t, 4, frozen.Length())
+		assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(frozen.All()))
+
+		x, err := frozen.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+
+		x, err = frozen.At(3)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, x)
+
+		_, err = frozen.At(4)
+		assert.ErrorIs(t, err, ErrIndexOutOfRange)
+
+		_, err = frozen.At(-1)
+		assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	})
+
+	t.Run("cycle repeats elements from the front", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+
+		var got []int
+		for x := range q.Cycle() {
+			got = append(got, x)
+			if len(got) == 7 {
+				break
+			}
+		}
+
+		assert.Equal(t, []int{1, 2, 3, 1, 2, 3, 1}, got)
+	})
+
+	t.Run("cycle yields nothing for empty queue", func(t *testing.T) {
+		q := createQueue(2)
+		assert.Empty(t, slices.Collect(q.Cycle()))
+	})
+
+	t.Run("cycle is unaffected by later changes", func(t *testing.T) {
+		q := createQueue(2)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		seq := q.Cycle()
+		_, _ = q.Pop()
+		assert.NoError(t, q.Push(3))
+
+		var got []int
+		for x := range seq {
+			got = append(got, x)
+			if len(got) == 4 {
+				break
+			}
+		}
+
+		assert.Equal(t, []int{1, 2, 1, 2}, got)
+	})
+
+	t.Run("pop during iteration yields originally captured elements", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+
+		var got []int
+		for x := range q.All() {
+			got = append(got, x)
+			_, err := q.Pop()
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+		assert.Equal(t, 0, q.Length())
+
+		q = createWrappedQueue(t, createQueue, 1, 2, 3, 4, 5)
+
+		got = nil
+		for x := range q.Backward() {
+			got = append(got, x)
+			_, err := q.Pop()
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, []int{5, 4, 3, 2, 1}, got)
+	})
+}
+
+func runBoundedQueueTests(t *testing.T, createQueue func(capacity int) *RingBuffer[int]) {
+	t.Helper()
+
+	runCommonQueueTests(t, createQueue)
+
+	t.Run("cannot push to full queue", func(t *testing.T) {
+		q := createQueue(1)
+		assert.NoError(t, q.Push(1))
+
+		err := q.Push(2)
+		assert.ErrorIs(t, err, ErrQueueFull)
+	})
+
+	t.Run("append stops when full", func(t *testing.T) {
+		q := createQueue(3)
+		assert.NoError(t, q.Push(1))
+		src := createWrappedQueue(t, createQueue, 2, 3, 4)
+
+		count, err := q.Append(src)
+		assert.ErrorIs(t, err, ErrQueueFull)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+		assert.Equal(t, []int{4}, slices.Collect(src.All()))
+	})
+
+	t.Run("push all atomic pushes whole batch or nothing", func(t *testing.T) {
+		q := createQueue(4)
+		assert.NoError(t, q.Push(1))
+
+		assert.NoError(t, q.PushAllAtomic(2, 3))
+		assert.Equal(t, 3, q.Length())
+
+		assert.ErrorIs(t, q.PushAllAtomic(4, 5), ErrQueueFull)
+		assert.Equal(t, 3, q.Length())
+
+		assert.NoError(t, q.PushAllAtomic(4))
+		assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(q.All()))
+	})
+
+	t.Run("replace all fits within capacity", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+
+		assert.NoError(t, q.ReplaceAll([]int{7, 8, 9}))
+		assert.Equal(t, []int{7, 8, 9}, slices.Collect(q.All()))
+	})
+
+	t.Run("replace all leaves queue empty when items do not fit", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+
+		assert.ErrorIs(t, q.ReplaceAll([]int{7, 8, 9, 10}), ErrQueueFull)
+		assert.Equal(t, 0, q.Length())
+		assert.NoError(t, q.Push(4))
+		assert.Equal(t, []int{4}, slices.Collect(q.All()))
+	})
+
+	t.Run("fill from channel stops when full", func(t *testing.T) {
+		q := createQueue(3)
+		ch := make(chan int, 5)
+		for i := range 5 {
+			ch <- i + 1
+		}
+		close(ch)
+
+		assert.Equal(t, 3, q.FillFromChannel(ch))
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+		assert.Len(t, ch, 2)
+	})
+
+	t.Run("set capacity changes maximum capacity", func(t *testing.T) {
+		q := createQueue(1)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.SetCapacity(2))
+		assert.NoError(t, q.Push(2))
+		assert.ErrorIs(t, q.Push(3), ErrQueueFull)
+	})
+}
+
+func runUnboundedQueueTests(t *testing.T, createQueue func(capacity int) *RingBuffer[int]) {
+	t.Helper()
+
+	runCommonQueueTests(t, createQueue)
+
+	t.Run("fill from channel receives until closed", func(t *testing.T) {
+		q := createQueue(2)
+		ch := make(chan int, 5)
+		for i := range 5 {
+			ch <- i + 1
+		}
+		close(ch)
+
+		assert.Equal(t, 5, q.FillFromChannel(ch))
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(q.All()))
+	})
+
+	t.Run("replace all grows storage", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2)
+
+		assert.NoError(t, q.ReplaceAll([]int{3, 4, 5, 6, 7}))
+		assert.Equal(t, []int{3, 4, 5, 6, 7}, slices.Collect(q.All()))
+		assert.NoError(t, q.ReplaceAll(nil))
+		assert.Equal(t, 0, q.Length())
+	})
+
+	t.Run("push all atomic grows storage", func(t *testing.T) {
+		q := createWrappedQueue(t, createQueue, 1, 2, 3)
+		assert.NoError(t, q.PushAllAtomic(4, 5, 6, 7, 8, 9, 10))
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, slices.Collect(q.All()))
+	})
+
+	t.Run("resize after pushing with no pops", func(t *testing.T) {
+		q := createQueue(2)
+		const itemCount = 4
+
+		for i := range itemCount {
+			assert.NoError(t, q.Push(i+1))
+		}
+
+		for i := range itemCount {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, i+1, x)
+		}
+	})
+
+	t.Run("resize after pushing with pop", func(t *testing.T) {
+		t.Run("initial capacity of two", func(t *testing.T) {
+			q := createQueue(2)
+			assert.NoError(t, q.Push(1))
+			assert.NoError(t, q.Push(2))
+			_, _ = q.Pop()
+			assert.NoError(t, q.Push(3))
+			assert.NoError(t, q.Push(4))
+
+			for i := range 3 {
+				x, err := q.Pop()
+				assert.NoError(t, err)
+				assert.Equal(t, i+2, x)
+			}
+		})
+
+		t.Run("initial capacity of five", func(t *testing.T) {
+			q := createQueue(5)
+			assert.NoError(t, q.Push(1))
+			assert.NoError(t, q.Push(2))
+			assert.NoError(t, q.Push(3))
+			assert.NoError(t, q.Push(4))
+			assert.NoError(t, q.Push(5))
+			_, _ = q.Pop()
+			_, _ = q.Pop()
+			_, _ = q.Pop()
+			assert.NoError(t, q.Push(6))
+			assert.NoError(t, q.Push(7))
+			assert.NoError(t, q.Push(8))
+			assert.NoError(t, q.Push(9))
+
+			for i := range 5 {
+				x, err := q.Pop()
+				assert.NoError(t, err)
+				assert.Equal(t, i+4, x)
+			}
+		})
+	})
+}
+
+func TestBoundedRingBufferQueue(t *testing.T) {
+	runBoundedQueueTests(t, func(capacity int) *RingBuffer[int] {
+		return NewBoundedRingBuffer[int](capacity)
+	})
+}
+
+func TestUnboundedRingBufferQueue(t *testing.T) {
+	runUnboundedQueueTests(t, func(capacity int) *RingBuffer[int] {
+		return NewUnboundedRingBuffer[int](capacity)
+	})
+}
+
+// newInvalidRingBufferQueue returns a queue whose internal state is deliberately inconsistent.
+func newInvalidRingBufferQueue(capacity, front, length int) *RingBuffer[int] {
+	return &RingBuffer[int]{
+		items:  make([]int, capacity),
+		front:  front,
+		length: length,
+	}
+}
+
+func TestRingBufferQueueValidate(t *testing.T) {
+	assert.NoError(t, newInvalidRingBufferQueue(4, 3, 4).Validate())
+	assert.ErrorIs(t, newInvalidRingBufferQueue(4, 4, 0).Validate(), ErrInvalidQueue)
+	assert.ErrorIs(t, newInvalidRingBufferQueue(4, -1, 0).Validate(), ErrInvalidQueue)
+	assert.ErrorIs(t, newInvalidRingBufferQueue(4, 0, 5).Validate(), ErrInvalidQueue)
+	assert.ErrorIs(t, newInvalidRingBufferQueue(4, 0, -1).Validate(), ErrInvalidQueue)
+	assert.NoError(t, newInvalidRingBufferQueue(0, 0, 0).Validate())
+	assert.ErrorIs(t, newInvalidRingBufferQueue(0, 1, 0).Validate(), ErrInvalidQueue)
+	assert.ErrorIs(t, newInvalidRingBufferQueue(0, 0, 1).Validate(), ErrInvalidQueue)
+	assert.NoError(t, NewStaticQueue([]int{}).Validate())
+}
+
+func TestNewStaticQueue(t *testing.T) {
+	backing := make([]int, 4, 8)
+
+	t.Run("uses backing storage with its length as capacity", func(t *testing.T) {
+		q := NewStaticQueue(backing)
+		assert.Equal(t, 4, q.Cap())
+
+		for i := range 4 {
+			assert.NoError(t, q.Push(i+1))
+		}
+
+		assert.ErrorIs(t, q.Push(5), ErrQueueFull)
+		assert.Equal(t, []int{1, 2, 3, 4}, backing)
+
+		_, _ = q.Pop()
+		assert.NoError(t, q.Push(5))
+		assert.Equal(t, []int{5, 2, 3, 4}, backing)
+		assert.Equal(t, []int{2, 3, 4, 5}, slices.Collect(q.All()))
+	})
+
+	t.Run("cannot set capacity", func(t *testing.T) {
+		q := NewStaticQueue(backing)
+		assert.ErrorIs(t, q.SetCapacity(8), ErrFixedStorage)
+		assert.Equal(t, 4, q.Cap())
+	})
+
+	t.Run("empty backing is always full", func(t *testing.T) {
+		q := NewStaticQueue([]int{})
+		assert.ErrorIs(t, q.Push(1), ErrQueueFull)
+		assert.Equal(t, 0, q.Skip(1))
+	})
+}
+
+func TestRingBufferRejectedCount(t *testing.T) {
+	q := NewBoundedRingBuffer[int](2)
+	assert.Equal(t, 0, q.RejectedCount())
+
+	for i := range 5 {
+		_ = q.Push(i)
+	}
+	assert.Equal(t, 3, q.RejectedCount())
+
+	_, _ = q.Pop()
+	assert.NoError(t, q.Push(5))
+	assert.ErrorIs(t, q.Push(6), ErrQueueFull)
+	assert.Equal(t, 4, q.RejectedCount())
+}
+
+func TestNewBoundedQueuePolicy(t *testing.T) {
+	pushAll := func(q Queue[int], items ...int) []error {
+		var errs []error
+		for _, item := range items {
+			errs = append(errs, q.Push(item))
+		}
+
+		return errs
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		q := NewBoundedQueuePolicy[int](3, Reject)
+		assert.Equal(t, []error{nil, nil, nil, ErrQueueFull, ErrQueueFull}, pushAll(q, 1, 2, 3, 4, 5))
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		q := NewBoundedQueuePolicy[int](3, DropOldest)
+		assert.Equal(t, []error{nil, nil, nil, nil, nil}, pushAll(q, 1, 2, 3, 4, 5))
+		assert.Equal(t, []int{3, 4, 5}, slices.Collect(q.All()))
+		assert.Equal(t, 0, q.RejectedCount())
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		q := NewBoundedQueuePolicy[int](3, DropNewest)
+		assert.Equal(t, []error{nil, nil, nil, nil, nil}, pushAll(q, 1, 2, 3, 4, 5))
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+	})
+
+	t.Run("split queues keep policy", func(t *testing.T) {
+		q := NewBoundedQueuePolicy[int](3, DropOldest)
+		assert.Equal(t, []error{nil, nil, nil}, pushAll(q, 1, 2, 3))
+
+		front, back, err := q.SplitAt(1)
+		assert.NoError(t, err)
+		assert.Equal(t, []error{nil, nil, nil}, pushAll(front, 4, 5, 6))
+		assert.Equal(t, []int{4, 5, 6}, slices.Collect(front.All()))
+		assert.Equal(t, []error{nil, nil}, pushAll(back, 7, 8))
+		assert.Equal(t, []int{3, 7, 8}, slices.Collect(back.All()))
+	})
+}
+
+func TestNewUnboundedQueueFrom(t *testing.T) {
+	src := NewBoundedQueue[int](3)
+	for i := range 3 {
+		assert.NoError(t, src.Push(i+1))
+	}
+
+	q := NewUnboundedQueueFrom(src)
+	assert.NoError(t, q.Push(4))
+	assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(q.All()))
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(src.All()))
+
+	assert.Equal(t, 0, NewUnboundedQueueFrom(NewBoundedQueue[int](1)).Length())
+
+	priority := NewBoundedPriorityQueue(3, func(a, b int) bool { return a < b })
+	for _, x := range []int{2, 3, 1} {
+		assert.NoError(t, priority.Push(x))
+	}
+
+	fifo := NewUnboundedQueueFrom[int](priority)
+	assert.Equal(t, []int{3, 2, 1}, slices.Collect(fifo.All()))
+	assert.Equal(t, 3, priority.Length())
+}
+
+func TestCollectSeq(t *testing.T) {
+	q := CollectSeq(slices.Values([]int{3, 1, 4, 1, 5}))
+	assert.Equal(t, []int{3, 1, 4, 1, 5}, slices.Collect(q.All()))
+
+	roundTrip := CollectSeq(q.All())
+	assert.Equal(t, []int{3, 1, 4, 1, 5}, slices.Collect(roundTrip.All()))
+
+	assert.Equal(t, 0, CollectSeq(slices.Values([]int(nil))).Length())
+}
+
+func TestFromMapSorted(t *testing.T) {
+	q := FromMapSorted(map[int]string{3: "c", -1: "a", 2: "b", 10: "d"})
+	assert.Equal(t, []string{"a", "b", "c", "d"}, slices.Collect(q.All()))
+
+	assert.Equal(t, 0, FromMapSorted(map[int]string(nil)).Length())
+}
+
+func TestNewUnboundedQueueForThroughput(t *testing.T) {
+	for _, tc := range []struct {
+		pushesPerSec, popsPerSec int
+		window                   time.Duration
+		want                     int
+	}{
+		{pushesPerSec: 100, popsPerSec: 60, window: 2 * time.Second, want: 80},
+		{pushesPerSec: 1000, popsPerSec: 0, window: 250 * time.Millisecond, want: 250},
+		{pushesPerSec: 50, popsPerSec: 100, window: time.Second, want: 1},
+		{pushesPerSec: 10, popsPerSec: 10, window: time.Minute, want: 1},
+	} {
+		q := NewUnboundedQueueForThroughput[int](tc.pushesPerSec, tc.popsPerSec, tc.window)
+		assert.Equal(t, tc.want, q.Cap(), "%+v", tc)
+	}
+}
+
+func TestNewUnboundedQueueReserve(t *testing.T) {
+	for _, expectedMax := range []int{1, 5, 8, 100} {
+		q := NewUnboundedQueueReserve[int](expectedMax)
+		initialCap := q.Cap()
+		assert.GreaterOrEqual(t, initialCap, expectedMax)
+		assert.Zero(t, initialCap&(initialCap-1), "capacity %d is not a power of two", initialCap)
+
+		for i := range expectedMax {
+			assert.NoError(t, q.Push(i))
+			assert.Equal(t, initialCap, q.Cap())
+		}
+	}
+
+	assert.Equal(t, 8, NewUnboundedQueueReserve[int](5).Cap())
+	assert.Equal(t, defaultRingBufferQueueCapacity, NewUnboundedQueueReserve[int](0).Cap())
+}
+
+type fakeLatencyRecorder struct {
+	pushes []time.Duration
+	pops   []time.Duration
+}
+
+func (r *fakeLatencyRecorder) RecordPush(d time.Duration) {
+	r.pushes = append(r.pushes, d)
+}
+
+func (r *fakeLatencyRecorder) RecordPop(d time.Duration) {
+	r.pops = append(r.pops, d)
+}
+
+// steppingClock is a clock that advances by a fixed step each time it is read.
+type steppingClock struct {
+	fakeClock
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.Advance(c.step)
+	return c.now
+}
+
+func TestRingBufferQueueLatencyRecorder(t *testing.T) {
+	rec := &fakeLatencyRecorder{}
+	clock := &steppingClock{fakeClock: *newFakeClock(), step: time.Millisecond}
+	q := NewBoundedRingBuffer[int](2, WithLatencyRecorder(rec), WithClock(clock))
+
+	assert.NoError(t, q.Push(1))
+	assert.NoError(t, q.Push(2))
+	assert.ErrorIs(t, q.Push(3), ErrQueueFull)
+
+	_, err := q.Pop()
+	assert.NoError(t, err)
+	_, _ = q.Pop()
+	_, err = q.Pop()
+	assert.ErrorIs(t, err, ErrQueueEmpty)
+
+	assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond}, rec.pushes)
+	assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond}, rec.pops)
+}
+
+func TestRingBufferQueueLazyAllocation(t *testing.T) {
+	q := NewUnboundedRingBuffer[int](1<<20, WithLazyAllocation())
+	assert.Equal(t, lazyRingBufferQueueCapacity, q.Cap())
+
+	for i := range lazyRingBufferQueueCapacity + 1 {
+		assert.NoError(t, q.Push(i))
+	}
+
+	assert.Equal(t, 2*lazyRingBufferQueueCapacity, q.Cap())
+	assert.Equal(t, 4, NewUnboundedRingBuffer[int](4, WithLazyAllocation()).Cap())
+	assert.Equal(t, 1<<20, NewBoundedRingBuffer[int](1<<20, WithLazyAllocation()).Cap())
+}
+
+func TestRingBufferQueueCompactThreshold(t *testing.T) {
+	createWrapped := func(opts ...Option) *RingBuffer[int] {
+		q := NewBoundedRingBuffer[int](4, opts...)
+		for i := range 4 {
+			assert.NoError(t, q.Push(i+1))
+		}
+
+		_, _ = q.Pop()
+		_, _ = q.Pop()
+		assert.NoError(t, q.Push(5))
+		assert.NoError(t, q.Push(6))
+
+		return q
+	}
+
+	t.Run("compacts once threshold is exceeded", func(t *testing.T) {
+		q := createWrapped(WithCompactThreshold(0.5))
+		assert.False(t, q.IsContiguous())
+
+		x, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, x)
+		assert.True(t, q.IsContiguous())
+		assert.Equal(t, 0, q.front)
+		assert.Equal(t, []int{4, 5, 6}, slices.Collect(q.All()))
+	})
+
+	t.Run("does not compact without option", func(t *testing.T) {
+		q := createWrapped()
+
+		_, err := q.Pop()
+		assert.NoError(t, err)
+		assert.False(t, q.IsContiguous())
+		assert.Equal(t, []int{4, 5, 6}, slices.Collect(q.All()))
+	})
+
+	t.Run("compacts contiguous elements", func(t *testing.T) {
+		q := NewUnboundedRingBuffer[int](8, WithCompactThreshold(0.25))
+		for i := range 6 {
+			assert.NoError(t, q.Push(i+1))
+		}
+
+		for range 3 {
+			_, _ = q.Pop()
+		}
+
+		assert.Equal(t, 0, q.front)
+		assert.Equal(t, []int{4, 5, 6}, slices.Collect(q.All()))
+	})
+}
+
+func TestSharesBacking(t *testing.T) {
+	q := NewBoundedRingBuffer[int](4)
+	assert.NoError(t, q.Push(1))
+	assert.NoError(t, q.Push(2))
+
+	assert.True(t, SharesBacking[int](q, q))
+	assert.True(t, SharesBacking(q, q.Freeze()))
+	assert.True(t, SharesBacking(q.Freeze(), q.Freeze()))
+	assert.False(t, SharesBacking(q.Freeze(), NewUnboundedQueueFrom[int](q).Freeze()))
+	assert.False(t, SharesBacking(NewUnboundedQueue[int](4), q.Freeze()))
+
+	assert.False(t, SharesBacking(q, NewUnboundedQueueFrom[int](q)))
+
+	front, back, err := q.SplitAt(1)
+	assert.NoError(t, err)
+	assert.False(t, SharesBacking[int](q, front))
+	assert.False(t, SharesBacking[int](q, back))
+
+	static := NewStaticQueue(make([]int, 0))
+	assert.False(t, SharesBacking(static, static))
+}
+
+func TestRingBufferBatch(t *testing.T) {
+	t.Run("prepare grows unbounded storage and commit appends", func(t *testing.T) {
+		q := NewUnboundedRingBuffer[int](4)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+		_, _ = q.Pop()
+
+		batch := q.PrepareForBatch(6)
+		assert.Len(t, batch, 6)
+		assert.Equal(t, 0, q.front)
+
+		for i := range batch {
+			batch[i] = i + 3
+		}
+
+		q.CommitBatch(len(batch))
+		assert.Equal(t, []int{2, 3, 4, 5, 6, 7, 8}, slices.Collect(q.All()))
+	})
+
+	t.Run("prepare compacts wrapped elements", func(t *testing.T) {
+		q := NewUnboundedRingBuffer[int](4)
+		for i := range 4 {
+			assert.NoError(t, q.Push(i+1))
+		}
+
+		_, _ = q.Pop()
+		_, _ = q.Pop()
+		assert.NoError(t, q.Push(5))
+
+		batch := q.PrepareForBatch(1)
+		assert.Equal(t, 4, q.Cap())
+		assert.True(t, q.IsContiguous())
+
+		batch[0] = 6
+		q.CommitBatch(1)
+		assert.Equal(t, []int{3, 4, 5, 6}, slices.Collect(q.All()))
+	})
+
+	t.Run("prepare is limited by bounded capacity", func(t *testing.T) {
+		q := NewBoundedRingBuffer[int](3)
+		assert.NoError(t, q.Push(1))
+
+		batch := q.PrepareForBatch(5)
+		assert.Len(t, batch, 2)
+		batch[0], batch[1] = 2, 3
+		q.CommitBatch(2)
+
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(q.All()))
+		assert.Panics(t, func() { q.CommitBatch(1) })
+	})
+
+	t.Run("prepare panics on negative size", func(t *testing.T) {
+		q := NewUnboundedRingBuffer[int](2)
+		assert.PanicsWithValue(t, "queue: batch size out of range", func() { q.PrepareForBatch(-1) })
+		assert.Equal(t, 0, q.Length())
+	})
+}
+
+func TestRingBufferFreeSpans(t *testing.T) {
+	t.Run("free space around contiguous elements", func(t *testing.T) {
+		q := NewUnboundedRingBuffer[int](6)
+		for i := range 4 {
+			assert.NoError(t, q.Push(i))
+		}
+
+		_, _ = q.Pop()
+		_, _ = q.Pop()
+
+		first, second := q.FreeSpans()
+		assert.Len(t, first, 2)
+		assert.Len(t, second, 2)
+
+		first[0], first[1], second[0] = 4, 5, 6
+		q.CommitBatch(3)
+		assert.Equal(t, []int{2, 3, 4, 5, 6}, slices.Collect(q.All()))
+	})
+
+	t.Run("free space between wrapped elements", func(t *testing.T) {
+		q := NewUnboundedRingBuffer[int](6)
+		for i := range 6 {
+			assert.NoError(t, q.Push(i))
+		}
+
+		_, _ = q.Pop()
+		_, _ = q.Pop()
+		_, _ = q.Pop()
+		assert.NoError(t, q.Push(6))
+
+		first, second := q.FreeSpans()
+		assert.Len(t, first, 2)
+		assert.Empty(t, second)
+		assert.Equal(t, q.Cap()-q.Length(), len(first)+len(second))
+
+		first[0] = 7
+		q.CommitBatch(1)
+		assert.Equal(t, []int{3, 4, 5, 6, 7}, slices.Collect(q.All()))
+	})
+
+	t.Run("full ring buffer has no free space", func(t *testing.T) {
+		q := NewBoundedRingBuffer[int](2)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		first, second := q.FreeSpans()
+		assert.Empty(t, first)
+		assert.Empty(t, second)
+	})
+}
+
+func TestRingBufferDataSpans(t *testing.T) {
+	t.Run("contiguous elements are in first span", func(t *testing.T) {
+		q := NewUnboundedRingBuffer[int](4)
+		assert.NoError(t, q.Push(1))
+		assert.NoError(t, q.Push(2))
+
+		first, second := q.DataSpans()
+		assert.Equal(t, []int{1, 2}, first)
+		assert.Empty(t, second)
+	})
+
+	t.Run("wrapped elements are split between spans", func(t *testing.T) {
+		q := NewBoundedRingBuffer[int](4)
+		for i := range 4 {
+			assert.NoError(t, q.Push(i))
+		}
+
+		_, _ = q.Pop()
+		_, _ = q.Pop()
+		assert.NoError(t, q.Push(4))
+
+		first, second := q.DataSpans()
+		assert.Equal(t, []int{2, 3}, first)
+		assert.Equal(t, []int{4}, second)
+		assert.Equal(t, q.toSlice(), slices.Concat(first, second))
+	})
+
+	t.Run("empty ring buffer has no data", func(t *testing.T) {
+		first, second := NewBoundedRingBuffer[int](2).DataSpans()
+		assert.Empty(t, first)
+		assert.Empty(t, second)
+	})
+}
+
+func TestRingBufferBackIndex(t *testing.T) {
+	q := NewBoundedRingBuffer[int](3)
+	for i := range 3 {
+		assert.NoError(t, q.Push(i))
+	}
+
+	_, _ = q.Pop()
+	_, _ = q.Pop()
+	assert.Equal(t, 0, q.BackIndex())
+
+	q.items[q.BackIndex()] = 7
+	q.Advance(1)
+	assert.Equal(t, 1, q.BackIndex())
+	assert.Equal(t, []int{2, 7}, slices.Collect(q.All()))
+
+	q.items[q.BackIndex()] = 8
+	q.Advance(1)
+	assert.Panics(t, func() { q.Advance(1) })
+
+	for _, want := range []int{2, 7, 8} {
+		x, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, want, x)
+	}
+}
+
+func TestRingBufferWillResizeOnPush(t *testing.T) {
+	q := NewUnboundedRingBuffer[int](2)
+	assert.False(t, q.WillResizeOnPush())
+
+	assert.NoError(t, q.Push(1))
+	assert.False(t, q.WillResizeOnPush())
+
+	assert.NoError(t, q.Push(2))
+	assert.True(t, q.WillResizeOnPush())
+
+	assert.NoError(t, q.SetCapacity(4))
+	assert.False(t, q.WillResizeOnPush())
+
+	bounded := NewBoundedRingBuffer[int](1)
+	assert.NoError(t, bounded.Push(1))
+	assert.False(t, bounded.WillResizeOnPush())
+}
+
+func TestRingBufferEstimatedBytes(t *testing.T) {
+	q := NewBoundedRingBuffer[int64](10)
+	assert.Equal(t, 80, q.EstimatedBytes())
+
+	assert.NoError(t, q.Push(1))
+	assert.Equal(t, 80, q.EstimatedBytes())
+
+	assert.Equal(t, 0, NewBoundedRingBuffer[struct{}](4).EstimatedBytes())
+}
+
+func TestRingBufferSlack(t *testing.T) {
+	q := NewUnboundedRingBuffer[int](2)
+	assert.Equal(t, 2, q.Slack())
+
+	for i := range 3 {
+		assert.NoError(t, q.Push(i))
+	}
+
+	assert.Equal(t, 4, q.Cap())
+	assert.Equal(t, 1, q.Slack())
+
+	assert.Equal(t, 3, q.Skip(3))
+	assert.Equal(t, 4, q.Slack())
+
+	bounded := NewBoundedRingBuffer[int](2)
+	assert.NoError(t, bounded.Push(1))
+	assert.Equal(t, 1, bounded.Slack())
+}
+
+func TestRingBufferDebugState(t *testing.T) {
+	q := NewBoundedRingBuffer[int](4)
+	for i := range 4 {
+		assert.NoError(t, q.Push(i))
+	}
+
+	_, _ = q.Pop()
+	_, _ = q.Pop()
+	_, _ = q.Pop()
+	assert.NoError(t, q.Push(4))
+	assert.NoError(t, q.Push(5))
+
+	front, length, capacity, bounded := q.DebugState()
+	assert.Equal(t, 3, front)
+	assert.Equal(t, 3, length)
+	assert.Equal(t, 4, capacity)
+	assert.True(t, bounded)
+
+	_, _, _, bounded = NewUnboundedRingBuffer[int](1).DebugState()
+	assert.False(t, bounded)
+}
+
+func BenchmarkRingBufferPushPop(b *testing.B) {
+	const batchSize = 64
+
+	b.Run("direct", func(b *testing.B) {
+		q := NewUnboundedRingBuffer[int](batchSize)
+
+		for b.Loop() {
+			for i := range batchSize {
+				_ = q.Push(i)
+			}
+
+			for range batchSize {
+				_, _ = q.Pop()
+			}
+		}
+	})
+
+	b.Run("interface", func(b *testing.B) {
+		var q Queue[int] = NewUnboundedRingBuffer[int](batchSize)
+
+		for b.Loop() {
+			for i := range batchSize {
+				_ = q.Push(i)
+			}
+
+			for range batchSize {
+				_, _ = q.Pop()
+			}
+		}
+	})
 }
@@ -0,0 +1,69 @@
+package queue
+
+import (
+	"iter"
+	"time"
+)
+
+// RateLimitedQueue is a bounded queue that spaces out pops, refusing to pop an element until a minimum interval has
+// passed since the last element was popped.
+type RateLimitedQueue[Element any] struct {
+	items    Queue[Element]
+	interval time.Duration
+	lastPop  time.Time
+	popped   bool
+	clock    Clock
+}
+
+var _ Queue[int] = (*RateLimitedQueue[int])(nil)
+
+// NewRateLimitedQueue returns a new rate-limited queue that holds at most capacity elements and pops at most one
+// element per interval. The WithClock option controls the time used to measure the interval.
+func NewRateLimitedQueue[Element any](capacity int, interval time.Duration, opts ...Option) *RateLimitedQueue[Element] {
+	return &RateLimitedQueue[Element]{
+		items:    NewBoundedQueue[Element](capacity),
+		interval: interval,
+		clock:    newOptions(opts).clock,
+	}
+}
+
+// Push adds an element to the end of the queue. If the queue cannot accept more elements, the ErrQueueFull error is returned.
+func (q *RateLimitedQueue[Element]) Push(item Element) error {
+	return q.items.Push(item)
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+// If less than the interval has passed since the last successful pop, the ErrRateLimited error is returned and the
+// queue is unchanged.
+func (q *RateLimitedQueue[Element]) Pop() (Element, error) {
+	if q.items.Length() == 0 {
+		var item Element
+		return item, ErrQueueEmpty
+	}
+
+	now := q.clock.Now()
+	if q.popped && now.Sub(q.lastPop) < q.interval {
+		var item Element
+		return item, ErrRateLimited
+	}
+
+	q.lastPop, q.popped = now, true
+
+	return q.items.Pop()
+}
+
+// Peek returns the first element of the queue, whether or not it can be popped yet.
+// If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *RateLimitedQueue[Element]) Peek() (Element, error) {
+	return q.items.Peek()
+}
+
+// Length returns the number of elements in the queue.
+func (q *RateLimitedQueue[Element]) Length() int {
+	return q.items.Length()
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them.
+func (q *RateLimitedQueue[Element]) All() iter.Seq[Element] {
+	return q.items.All()
+}
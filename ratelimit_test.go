@@ -0,0 +1,54 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitedQueue(t *testing.T) {
+	t.Run("cannot pop from empty queue", func(t *testing.T) {
+		q := NewRateLimitedQueue[int](2, time.Second)
+
+		_, err := q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, err = q.Peek()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("cannot push to full queue", func(t *testing.T) {
+		q := NewRateLimitedQueue[int](1, time.Second)
+		assert.NoError(t, q.Push(1))
+		assert.ErrorIs(t, q.Push(2), ErrQueueFull)
+	})
+
+	t.Run("back to back pops are limited until interval passes", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewRateLimitedQueue[int](3, time.Second, WithClock(clock))
+		for i := range 3 {
+			assert.NoError(t, q.Push(i))
+		}
+
+		x, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, x)
+
+		_, err = q.Pop()
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, 2, q.Length())
+
+		clock.Advance(999 * time.Millisecond)
+		_, err = q.Pop()
+		assert.ErrorIs(t, err, ErrRateLimited)
+
+		clock.Advance(time.Millisecond)
+		x, err = q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, x)
+
+		_, err = q.Pop()
+		assert.ErrorIs(t, err, ErrRateLimited)
+	})
+}
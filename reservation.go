@@ -0,0 +1,95 @@
+package queue
+
+import "iter"
+
+// ReservableQueue is a bounded queue that lets callers reserve space for an element before it is available.
+// Each outstanding reservation counts against the queue's capacity until it is committed or cancelled, so a caller
+// holding a reservation is guaranteed room for its element.
+type ReservableQueue[Element any] struct {
+	items        *RingBuffer[Element]
+	reservations map[int]struct{}
+	nextToken    int
+}
+
+var _ Queue[int] = (*ReservableQueue[int])(nil)
+
+// NewReservableQueue returns a new reservable queue with a maximum specific capacity.
+func NewReservableQueue[Element any](capacity int) *ReservableQueue[Element] {
+	return &ReservableQueue[Element]{
+		items:        NewBoundedRingBuffer[Element](capacity),
+		reservations: make(map[int]struct{}),
+	}
+}
+
+// Reserve reserves space for one element, returning a token identifying the reservation and true.
+// If the queue has no unreserved space, it returns false.
+func (q *ReservableQueue[Element]) Reserve() (token int, ok bool) {
+	if q.free() == 0 {
+		return 0, false
+	}
+
+	q.nextToken++
+	q.reservations[q.nextToken] = struct{}{}
+
+	return q.nextToken, true
+}
+
+// CommitReservation fills a reservation by adding an element to the end of the queue.
+// If token does not identify an outstanding reservation, the ErrUnknownReservation error is returned.
+func (q *ReservableQueue[Element]) CommitReservation(token int, item Element) error {
+	if err := q.CancelReservation(token); err != nil {
+		return err
+	}
+
+	return q.items.Push(item)
+}
+
+// CancelReservation releases a reservation without adding an element.
+// If token does not identify an outstanding reservation, the ErrUnknownReservation error is returned.
+func (q *ReservableQueue[Element]) CancelReservation(token int) error {
+	if _, ok := q.reservations[token]; !ok {
+		return ErrUnknownReservation
+	}
+
+	delete(q.reservations, token)
+
+	return nil
+}
+
+// Push adds an element to the end of the queue. If the queue has no unreserved space, the ErrQueueFull error is returned.
+func (q *ReservableQueue[Element]) Push(item Element) error {
+	if q.free() == 0 {
+		return ErrQueueFull
+	}
+
+	return q.items.Push(item)
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *ReservableQueue[Element]) Pop() (Element, error) {
+	return q.items.Pop()
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *ReservableQueue[Element]) Peek() (Element, error) {
+	return q.items.Peek()
+}
+
+// Length returns the number of elements in the queue, excluding outstanding reservations.
+func (q *ReservableQueue[Element]) Length() int {
+	return q.items.Length()
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them.
+func (q *ReservableQueue[Element]) All() iter.Seq[Element] {
+	return q.items.All()
+}
+
+// Reserved returns the number of outstanding reservations.
+func (q *ReservableQueue[Element]) Reserved() int {
+	return len(q.reservations)
+}
+
+func (q *ReservableQueue[Element]) free() int {
+	return q.items.Cap() - q.items.Length() - len(q.reservations)
+}
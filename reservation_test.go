@@ -0,0 +1,62 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservableQueue(t *testing.T) {
+	t.Run("reservations count against capacity", func(t *testing.T) {
+		q := NewReservableQueue[int](3)
+		assert.NoError(t, q.Push(1))
+
+		first, ok := q.Reserve()
+		assert.True(t, ok)
+		second, ok := q.Reserve()
+		assert.True(t, ok)
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, 2, q.Reserved())
+
+		_, ok = q.Reserve()
+		assert.False(t, ok)
+		assert.ErrorIs(t, q.Push(2), ErrQueueFull)
+
+		assert.NoError(t, q.CommitReservation(first, 2))
+		assert.Equal(t, 2, q.Length())
+		assert.Equal(t, 1, q.Reserved())
+
+		assert.NoError(t, q.CancelReservation(second))
+		assert.Equal(t, 0, q.Reserved())
+		assert.NoError(t, q.Push(3))
+
+		for _, want := range []int{1, 2, 3} {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x)
+		}
+	})
+
+	t.Run("cancelled space can be reserved again", func(t *testing.T) {
+		q := NewReservableQueue[int](1)
+
+		token, ok := q.Reserve()
+		assert.True(t, ok)
+		assert.NoError(t, q.CancelReservation(token))
+
+		_, ok = q.Reserve()
+		assert.True(t, ok)
+	})
+
+	t.Run("unknown tokens are rejected", func(t *testing.T) {
+		q := NewReservableQueue[int](2)
+
+		token, ok := q.Reserve()
+		assert.True(t, ok)
+		assert.NoError(t, q.CommitReservation(token, 1))
+
+		assert.ErrorIs(t, q.CommitReservation(token, 2), ErrUnknownReservation)
+		assert.ErrorIs(t, q.CancelReservation(token), ErrUnknownReservation)
+		assert.Equal(t, 1, q.Length())
+	})
+}
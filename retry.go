@@ -0,0 +1,96 @@
+package queue
+
+import "iter"
+
+// RetryQueue is an unbounded queue for retrying the processing of elements a limited number of times. It counts the
+// attempts made at each element: popping an element starts an attempt, and requeueing it after a failed attempt
+// pushes it back for another, unless it has already had the maximum number of attempts, in which case it is dropped.
+// PopWithAttempts returns a RetryAttempt that Requeue takes back, so attempts are tracked per popped entry rather than
+// looked up by the element's value, which would require comparable elements and confuse equal ones.
+type RetryQueue[Element any] struct {
+	items       Queue[RetryAttempt[Element]]
+	maxAttempts int
+	onDrop      func(item Element, attempts int)
+}
+
+var _ Queue[int] = (*RetryQueue[int])(nil)
+
+// RetryAttempt holds an element popped from a RetryQueue and the number of the attempt that popping it started.
+type RetryAttempt[Element any] struct {
+	Item     Element
+	Attempts int
+}
+
+// NewRetryQueue returns a new retry queue with the specific initial capacity, which allows each element up to
+// maxAttempts attempts. When a requeued element is dropped, onDrop is called with it and its number of attempts,
+// unless onDrop is nil.
+func NewRetryQueue[Element any](initialCapacity, maxAttempts int, onDrop func(item Element, attempts int)) *RetryQueue[Element] {
+	return &RetryQueue[Element]{
+		items:       NewUnboundedQueue[RetryAttempt[Element]](initialCapacity),
+		maxAttempts: maxAttempts,
+		onDrop:      onDrop,
+	}
+}
+
+// Push adds a new element, which has had no attempts, to the end of the queue. It always returns nil.
+func (q *RetryQueue[Element]) Push(item Element) error {
+	return q.items.Push(RetryAttempt[Element]{Item: item})
+}
+
+// Pop removes and returns the first element of the queue, starting an attempt at it. An element popped this way
+// cannot be requeued; use PopWithAttempts for elements that may need to be retried.
+// If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *RetryQueue[Element]) Pop() (Element, error) {
+	attempt, err := q.PopWithAttempts()
+	return attempt.Item, err
+}
+
+// PopWithAttempts removes the first element of the queue, starting an attempt at it, and returns the attempt, whose
+// number is one for an element that has not been requeued. The attempt is passed to Requeue if it fails.
+// If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *RetryQueue[Element]) PopWithAttempts() (RetryAttempt[Element], error) {
+	attempt, err := q.items.Pop()
+	if err != nil {
+		return attempt, err
+	}
+
+	attempt.Attempts++
+
+	return attempt, nil
+}
+
+// Requeue pushes the element of a failed attempt back onto the end of the queue for another attempt. If the element
+// has already had the maximum number of attempts, it is dropped and reported to the queue's drop callback instead.
+func (q *RetryQueue[Element]) Requeue(attempt RetryAttempt[Element]) {
+	if attempt.Attempts >= q.maxAttempts {
+		if q.onDrop != nil {
+			q.onDrop(attempt.Item, attempt.Attempts)
+		}
+
+		return
+	}
+
+	_ = q.items.Push(attempt)
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *RetryQueue[Element]) Peek() (Element, error) {
+	attempt, err := q.items.Peek()
+	return attempt.Item, err
+}
+
+// Length returns the number of elements in the queue, which excludes popped elements that have not been requeued.
+func (q *RetryQueue[Element]) Length() int {
+	return q.items.Length()
+}
+
+// All returns an iterator over the elements of the queue, from front to back, without removing them.
+func (q *RetryQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		for attempt := range q.items.All() {
+			if !yield(attempt.Item) {
+				return
+			}
+		}
+	}
+}
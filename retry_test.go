@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryQueue(t *testing.T) {
+	t.Run("cannot pop or peek empty queue", func(t *testing.T) {
+		q := NewRetryQueue[string](1, 3, nil)
+
+		_, err := q.PopWithAttempts()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, err = q.Peek()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("requeue increments attempts", func(t *testing.T) {
+		q := NewRetryQueue[string](2, 3, nil)
+		assert.NoError(t, q.Push("a"))
+		assert.NoError(t, q.Push("b"))
+
+		attempt, err := q.PopWithAttempts()
+		assert.NoError(t, err)
+		assert.Equal(t, RetryAttempt[string]{Item: "a", Attempts: 1}, attempt)
+
+		q.Requeue(attempt)
+		assert.Equal(t, 2, q.Length())
+
+		x, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, "b", x)
+
+		attempt, err = q.PopWithAttempts()
+		assert.NoError(t, err)
+		assert.Equal(t, RetryAttempt[string]{Item: "a", Attempts: 2}, attempt)
+	})
+
+	t.Run("equal elements keep their own attempts", func(t *testing.T) {
+		q := NewRetryQueue[string](2, 3, nil)
+		assert.NoError(t, q.Push("a"))
+
+		first, err := q.PopWithAttempts()
+		assert.NoError(t, err)
+
+		q.Requeue(first)
+		assert.NoError(t, q.Push("a"))
+
+		second, err := q.PopWithAttempts()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, second.Attempts)
+
+		third, err := q.PopWithAttempts()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, third.Attempts)
+	})
+
+	t.Run("requeue drops element after max attempts", func(t *testing.T) {
+		var dropped []string
+		var droppedAttempts []int
+		q := NewRetryQueue(1, 2, func(item string, attempts int) {
+			dropped = append(dropped, item)
+			droppedAttempts = append(droppedAttempts, attempts)
+		})
+		assert.NoError(t, q.Push("a"))
+
+		for want := 1; want <= 2; want++ {
+			attempt, err := q.PopWithAttempts()
+			assert.NoError(t, err)
+			assert.Equal(t, "a", attempt.Item)
+			assert.Equal(t, want, attempt.Attempts)
+			q.Requeue(attempt)
+		}
+
+		assert.Equal(t, 0, q.Length())
+		assert.Equal(t, []string{"a"}, dropped)
+		assert.Equal(t, []int{2}, droppedAttempts)
+	})
+}
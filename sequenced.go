@@ -0,0 +1,52 @@
+package queue
+
+// SequencedQueue is an unbounded queue that assigns each pushed element a sequence number, starting at one and
+// increasing by one with each push. The sequence numbers make the order of insertion visible when elements are popped.
+type SequencedQueue[Element any] struct {
+	items Queue[sequencedElement[Element]]
+	next  uint64
+}
+
+type sequencedElement[Element any] struct {
+	item Element
+	seq  uint64
+}
+
+// NewSequencedQueue returns a new sequenced queue with the specific initial capacity.
+func NewSequencedQueue[Element any](initialCapacity int) *SequencedQueue[Element] {
+	return &SequencedQueue[Element]{
+		items: NewUnboundedQueue[sequencedElement[Element]](initialCapacity),
+	}
+}
+
+// Push adds an element to the end of the queue and returns the sequence number assigned to it.
+func (q *SequencedQueue[Element]) Push(item Element) uint64 {
+	q.next++
+	_ = q.items.Push(sequencedElement[Element]{item: item, seq: q.next})
+
+	return q.next
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *SequencedQueue[Element]) Pop() (Element, error) {
+	item, _, err := q.PopSeq()
+	return item, err
+}
+
+// PopSeq removes and returns the first element of the queue along with the sequence number assigned when it was
+// pushed. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *SequencedQueue[Element]) PopSeq() (Element, uint64, error) {
+	entry, err := q.items.Pop()
+	return entry.item, entry.seq, err
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *SequencedQueue[Element]) Peek() (Element, error) {
+	entry, err := q.items.Peek()
+	return entry.item, err
+}
+
+// Length returns the number of elements in the queue.
+func (q *SequencedQueue[Element]) Length() int {
+	return q.items.Length()
+}
@@ -0,0 +1,42 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequencedQueue(t *testing.T) {
+	t.Run("cannot pop from empty queue", func(t *testing.T) {
+		q := NewSequencedQueue[int](1)
+
+		_, seq, err := q.PopSeq()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+		assert.Equal(t, uint64(0), seq)
+	})
+
+	t.Run("sequence numbers increase and follow elements through resize", func(t *testing.T) {
+		q := NewSequencedQueue[string](2)
+
+		assert.Equal(t, uint64(1), q.Push("a"))
+		assert.Equal(t, uint64(2), q.Push("b"))
+
+		x, seq, err := q.PopSeq()
+		assert.NoError(t, err)
+		assert.Equal(t, "a", x)
+		assert.Equal(t, uint64(1), seq)
+
+		assert.Equal(t, uint64(3), q.Push("c"))
+		assert.Equal(t, uint64(4), q.Push("d"))
+		assert.Equal(t, 3, q.Length())
+
+		for i, want := range []string{"b", "c", "d"} {
+			x, seq, err := q.PopSeq()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x)
+			assert.Equal(t, uint64(i+2), seq)
+		}
+
+		assert.Equal(t, uint64(5), q.Push("e"))
+	})
+}
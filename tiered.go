@@ -0,0 +1,78 @@
+package queue
+
+import "iter"
+
+// TieredQueue is an unbounded queue made of a bounded primary tier and an unbounded secondary tier, which takes the
+// elements pushed while the primary tier is full. Elements are popped in the order they were pushed, whichever tier
+// holds them. As elements are popped from the primary tier, elements move up from the secondary tier to replace them.
+type TieredQueue[Element any] struct {
+	primary   Queue[Element]
+	secondary Queue[Element]
+}
+
+var _ Queue[int] = (*TieredQueue[int])(nil)
+
+// NewTieredQueue returns a new tiered queue whose primary tier holds at most primaryCap elements.
+func NewTieredQueue[Element any](primaryCap int) *TieredQueue[Element] {
+	return &TieredQueue[Element]{
+		primary:   NewBoundedQueue[Element](primaryCap),
+		secondary: NewUnboundedQueue[Element](0),
+	}
+}
+
+// Push adds an element to the end of the queue, in the secondary tier if the primary tier is full. It always returns nil.
+func (q *TieredQueue[Element]) Push(item Element) error {
+	// the primary tier only takes elements while none are waiting behind it, to keep the order of pops.
+	if q.secondary.Length() == 0 && q.primary.Push(item) == nil {
+		return nil
+	}
+
+	return q.secondary.Push(item)
+}
+
+// Pop removes and returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *TieredQueue[Element]) Pop() (Element, error) {
+	item, err := q.primary.Pop()
+	if err != nil {
+		return item, err
+	}
+
+	if next, err := q.secondary.Peek(); err == nil && q.primary.Push(next) == nil {
+		_, _ = q.secondary.Pop()
+	}
+
+	return item, nil
+}
+
+// Peek returns the first element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *TieredQueue[Element]) Peek() (Element, error) {
+	return q.primary.Peek()
+}
+
+// Length returns the number of elements in the queue, across both tiers.
+func (q *TieredQueue[Element]) Length() int {
+	return q.primary.Length() + q.secondary.Length()
+}
+
+// All returns an iterator over the elements of the queue, from front to back across both tiers, without removing them.
+func (q *TieredQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		for _, tier := range []Queue[Element]{q.primary, q.secondary} {
+			for item := range tier.All() {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// PrimaryLength returns the number of elements in the primary tier.
+func (q *TieredQueue[Element]) PrimaryLength() int {
+	return q.primary.Length()
+}
+
+// SecondaryLength returns the number of elements in the secondary tier.
+func (q *TieredQueue[Element]) SecondaryLength() int {
+	return q.secondary.Length()
+}
@@ -0,0 +1,47 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTieredQueue(t *testing.T) {
+	t.Run("cannot pop or peek empty queue", func(t *testing.T) {
+		q := NewTieredQueue[int](2)
+
+		_, err := q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, err = q.Peek()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("spills past primary capacity and pops in order", func(t *testing.T) {
+		q := NewTieredQueue[int](3)
+		for i := range 5 {
+			assert.NoError(t, q.Push(i))
+		}
+
+		assert.Equal(t, 3, q.PrimaryLength())
+		assert.Equal(t, 2, q.SecondaryLength())
+		assert.Equal(t, 5, q.Length())
+
+		x, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, x)
+		assert.Equal(t, 3, q.PrimaryLength())
+		assert.Equal(t, 1, q.SecondaryLength())
+
+		assert.NoError(t, q.Push(5))
+		assert.Equal(t, 2, q.SecondaryLength())
+
+		for want := 1; want <= 5; want++ {
+			x, err := q.Pop()
+			assert.NoError(t, err)
+			assert.Equal(t, want, x)
+		}
+
+		assert.Equal(t, 0, q.Length())
+	})
+}
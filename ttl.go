@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"iter"
+	"time"
+)
+
+// TTLQueue is an unbounded queue whose elements expire once they have been in the queue for longer than its
+// time-to-live. Expired elements are discarded from the front of the queue whenever it is used.
+type TTLQueue[Element any] struct {
+	items Queue[ttlElement[Element]]
+	ttl   time.Duration
+	clock Clock
+}
+
+var _ Queue[int] = (*TTLQueue[int])(nil)
+
+type ttlElement[Element any] struct {
+	item     Element
+	pushedAt time.Time
+}
+
+// NewTTLQueue returns a new queue whose elements expire after ttl.
+// The WithClock option controls the time used to timestamp and expire elements.
+func NewTTLQueue[Element any](ttl time.Duration, opts ...Option) *TTLQueue[Element] {
+	return &TTLQueue[Element]{
+		items: NewUnboundedQueue[ttlElement[Element]](0),
+		ttl:   ttl,
+		clock: newOptions(opts).clock,
+	}
+}
+
+// Push adds an element to the end of the queue, timestamped with the current time. It always returns nil.
+func (q *TTLQueue[Element]) Push(item Element) error {
+	return q.items.Push(ttlElement[Element]{item: item, pushedAt: q.clock.Now()})
+}
+
+// Pop removes and returns the first unexpired element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *TTLQueue[Element]) Pop() (Element, error) {
+	q.removeExpired()
+
+	entry, err := q.items.Pop()
+	return entry.item, err
+}
+
+// Peek returns the first unexpired element of the queue. If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *TTLQueue[Element]) Peek() (Element, error) {
+	q.removeExpired()
+
+	entry, err := q.items.Peek()
+	return entry.item, err
+}
+
+// Length returns the number of unexpired elements in the queue.
+func (q *TTLQueue[Element]) Length() int {
+	q.removeExpired()
+	return q.items.Length()
+}
+
+// All returns an iterator over the unexpired elements of the queue, from front to back, without removing them.
+func (q *TTLQueue[Element]) All() iter.Seq[Element] {
+	return func(yield func(Element) bool) {
+		q.removeExpired()
+
+		for entry := range q.items.All() {
+			if !yield(entry.item) {
+				return
+			}
+		}
+	}
+}
+
+// OldestAge returns how long the first unexpired element of the queue has been in the queue, as of now. Elements that
+// have expired as of now are discarded first, so the age returned is always less than the queue's time-to-live.
+// If the queue is empty, the ErrQueueEmpty error is returned.
+func (q *TTLQueue[Element]) OldestAge(now time.Time) (time.Duration, error) {
+	q.removeExpiredAt(now)
+
+	entry, err := q.items.Peek()
+	if err != nil {
+		return 0, err
+	}
+
+	return now.Sub(entry.pushedAt), nil
+}
+
+func (q *TTLQueue[Element]) removeExpired() {
+	q.removeExpiredAt(q.clock.Now())
+}
+
+func (q *TTLQueue[Element]) removeExpiredAt(now time.Time) {
+	for {
+		entry, err := q.items.Peek()
+		if err != nil || now.Sub(entry.pushedAt) < q.ttl {
+			return
+		}
+
+		_, _ = q.items.Pop()
+	}
+}
@@ -0,0 +1,92 @@
+package queue
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLQueue(t *testing.T) {
+	t.Run("cannot pop from empty queue", func(t *testing.T) {
+		q := NewTTLQueue[int](time.Minute, WithClock(newFakeClock()))
+
+		_, err := q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+
+		_, err = q.OldestAge(time.Now())
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("expired elements are discarded", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewTTLQueue[int](time.Minute, WithClock(clock))
+
+		assert.NoError(t, q.Push(1))
+		clock.Advance(30 * time.Second)
+		assert.NoError(t, q.Push(2))
+		assert.Equal(t, 2, q.Length())
+
+		clock.Advance(30 * time.Second)
+		assert.Equal(t, 1, q.Length())
+
+		x, err := q.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+
+		clock.Advance(30 * time.Second)
+		_, err = q.Pop()
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+
+	t.Run("all yields unexpired elements", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewTTLQueue[int](time.Minute, WithClock(clock))
+
+		assert.NoError(t, q.Push(1))
+		clock.Advance(30 * time.Second)
+		assert.NoError(t, q.Push(2))
+		assert.NoError(t, q.Push(3))
+		clock.Advance(30 * time.Second)
+
+		assert.Equal(t, []int{2, 3}, slices.Collect(q.All()))
+		assert.Equal(t, 5, Sum[int](q))
+	})
+
+	t.Run("oldest age reports age of front element", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewTTLQueue[int](time.Minute, WithClock(clock))
+
+		assert.NoError(t, q.Push(1))
+		clock.Advance(10 * time.Second)
+		assert.NoError(t, q.Push(2))
+		clock.Advance(15 * time.Second)
+
+		age, err := q.OldestAge(clock.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, 25*time.Second, age)
+
+		_, _ = q.Pop()
+		age, err = q.OldestAge(clock.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, 15*time.Second, age)
+	})
+
+	t.Run("oldest age expires elements as of now", func(t *testing.T) {
+		clock := newFakeClock()
+		q := NewTTLQueue[int](time.Minute, WithClock(clock))
+
+		assert.NoError(t, q.Push(1))
+		clock.Advance(30 * time.Second)
+		assert.NoError(t, q.Push(2))
+
+		age, err := q.OldestAge(clock.Now().Add(45 * time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, 45*time.Second, age)
+		assert.Equal(t, 1, q.Length())
+
+		_, err = q.OldestAge(clock.Now().Add(2 * time.Minute))
+		assert.ErrorIs(t, err, ErrQueueEmpty)
+	})
+}